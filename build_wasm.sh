@@ -1,4 +1,7 @@
 cd wasm/
-cp "$(go env GOROOT)/misc/wasm/wasm_exec.js" ../liveview/assets/
+# wasm_exec.js is in lib/wasm since go 1.24, before in misc/wasm
+WASM_EXEC="$(go env GOROOT)/lib/wasm/wasm_exec.js"
+[ -f "$WASM_EXEC" ] || WASM_EXEC="$(go env GOROOT)/misc/wasm/wasm_exec.js"
+cp "$WASM_EXEC" ../liveview/assets/
 GOOS=js GOARCH=wasm go build -o  ../liveview/assets/json.wasm
 cd -
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"github.com/arturoeanton/go-fiber-live-view/liveview/components"
 	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 func main() {
@@ -14,11 +15,12 @@ func main() {
 		Title:  "Example1",
 		Path:   "/",
 		Router: app,
+		CSP:    view.NewCSP(),
 	}
 
 	home.Register(func() view.LiveDriver {
 		view.New("clock1", &components.Clock{})
-		return view.NewLayout("layout1", `
+		return view.NewLayout("layout1_"+uuid.NewString(), `
 		<div id="d2">{{mount "clock1"}}</div>
 		`)
 	})
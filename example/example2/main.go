@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"html"
 	"strings"
 	"sync"
 	"time"
@@ -63,13 +64,13 @@ func main() {
 
 				// Actualizar UI
 				spanNickname := document.GetDriverById("span_text_nickname")
-				spanNickname.FillValue(fmt.Sprint(data))
+				spanNickname.FillValue(html.EscapeString(fmt.Sprint(data)))
 				view.SendToAllLayouts("NEW_USER")
 			})
 
 		view.New("text_msg", &components.InputText{})
 		view.NewWithTemplate("select_to", `
-			<select onchange="send_event(this.id,'Change',this.value)" id="{{.IdComponent}}">
+			<select id="{{.IdComponent}}">
 				{{range $index, $element := .GetDriver.Data}}
 					<option value="{{$index}}">{{$element}}</option>
 				{{end}}
@@ -103,7 +104,7 @@ func main() {
 			if strings.HasPrefix(msg, "MSG|") {
 				msg = strings.TrimPrefix(msg, "MSG|")
 				chatBox := document.GetDriverById("div_general_chat")
-				chatBox.FillValue(fmt.Sprint(chatBox.GetHTML(), msg, "<br/>"))
+				chatBox.FillValue(fmt.Sprint(chatBox.GetHTML(), html.EscapeString(msg), "<br/>"))
 			}
 			if msg == "NEW_USER" {
 				// Actualizar lista de usuarios en el selector
@@ -1,17 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"log"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var (
-	todos = make(map[string]*Todo)
-	tasks = make(map[string]Task)
+	todos     = make(map[string]*Todo)
+	tasks     = make(map[string]Task)
+	templates = view.NewDirTemplateLoader(".")
 )
 
 type Task struct {
@@ -19,12 +26,19 @@ type Task struct {
 	State *int    `json:"state,omitempty"`
 }
 
+// NewTask es la tarea que se esta escribiendo, ligada a los inputs con lv-model
+type NewTask struct {
+	Name  string `validate:"required,max=100"`
+	State int    `validate:"required,min=1,max=3"`
+}
+
 type Todo struct {
 	*view.ComponentDriver[*Todo]
 	ParentId   string
 	ActualTime string
-	code       string
 	Tasks      *map[string]Task
+	NewTask    NewTask
+	Errors     view.FieldErrors
 }
 
 type Message struct {
@@ -44,16 +58,18 @@ func (t *Todo) Start() {
 }
 
 func (t *Todo) GetTemplate() string {
-	if t.code == "" {
-		t.code, _ = view.FileToString("todo.html")
-	}
-	return t.code
+	return templates.Template("todo.html")
 }
 
 func (t *Todo) Add(data interface{}) {
-	name := t.GetElementById("new_name")
-	stateStr := t.GetElementById("new_state")
-	state, _ := strconv.Atoi(stateStr)
+	t.Errors = view.Validate(&t.NewTask)
+	if len(t.Errors) > 0 {
+		t.Commit()
+		return
+	}
+	name := t.NewTask.Name
+	state := t.NewTask.State
+	t.NewTask = NewTask{State: 1}
 	id := uuid.NewString()
 	task := Task{
 		Name:  &name,
@@ -94,10 +110,15 @@ func (t *Todo) RemoveTask(data interface{}) {
 }
 
 func (t *Todo) Change(data interface{}) {
-	id := data.(string)
+	// data es "id:valor", lv-value con el valor del input
+	id, _, _ := strings.Cut(data.(string), ":")
 	name := t.GetElementById("name_" + id)
 	stateStr := t.GetElementById("state_" + id)
-	state, _ := strconv.Atoi(stateStr)
+	state, err := strconv.Atoi(stateStr)
+	if err != nil || state < 1 || state > 3 {
+		log.Println("invalid state", stateStr)
+		return
+	}
 	task := Task{
 		Name:  &name,
 		State: &state,
@@ -116,7 +137,33 @@ func (t *Todo) Change(data interface{}) {
 	view.SendToAllLayouts(string(msgJson))
 }
 
+// Export descarga las tareas como csv
+func (t *Todo) Export(data interface{}) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Write([]string{"id", "name", "state"})
+	for id, task := range *t.Tasks {
+		name, state := "", 0
+		if task.Name != nil {
+			name = *task.Name
+		}
+		if task.State != nil {
+			state = *task.State
+		}
+		w.Write([]string{id, name, strconv.Itoa(state)})
+	}
+	w.Flush()
+	if err := t.Download("tasks.csv", "text/csv", buf); err != nil {
+		log.Println("Export:", err)
+	}
+}
+
 func main() {
+	// recarga todo.html en caliente mientras se desarrolla: LIVEVIEW_DEV=1 go run .
+	if os.Getenv("LIVEVIEW_DEV") != "" {
+		templates.Watch(time.Second)
+	}
+
 	app := fiber.New()
 	home := view.PageControl{
 		Title:    "Todo",
@@ -133,6 +180,7 @@ func main() {
 		todo := &Todo{
 			ParentId: idLayout,
 			Tasks:    &tasks,
+			NewTask:  NewTask{State: 1},
 		}
 		todos[idLayout] = todo
 		view.New("todo", todo)
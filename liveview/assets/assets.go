@@ -0,0 +1,8 @@
+// Package assets has the client of the live views: the wasm module, the go runtime
+// that loads it and liveview.js that starts it, build them with build_wasm.sh
+package assets
+
+import "embed"
+
+//go:embed json.wasm wasm_exec.js liveview.js
+var FS embed.FS
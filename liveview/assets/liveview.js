@@ -0,0 +1,10 @@
+// liveview.js start the client of the live views, it is loaded by the page shell after
+// wasm_exec.js so the page does not need inline scripts
+(function () {
+	var script = document.currentScript;
+	var base = script.src.substring(0, script.src.lastIndexOf("/") + 1);
+	var go = new Go();
+	WebAssembly.instantiateStreaming(fetch(base + "json.wasm"), go.importObject).then(function (result) {
+		go.run(result.instance);
+	});
+})();
@@ -0,0 +1,82 @@
+package components
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+type Autocomplete struct {
+	*view.ComponentDriver[*Autocomplete]
+	Value       string
+	Placeholder string
+	Suggestions []string
+	// Source return the suggestions of the text written
+	Source func(text string) []string
+	// MinLength is the length of the text needed to look for suggestions
+	MinLength int
+	onSelect  func(c *Autocomplete, value string)
+}
+
+func (t *Autocomplete) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Autocomplete) Start() {
+	t.Commit()
+}
+
+func (t *Autocomplete) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" class="autocomplete" style="position:relative">
+	<input type="text" id="{{.IdComponent}}_input" value="{{.Value}}" placeholder="{{.Placeholder}}" autocomplete="off"
+		lv-keyup="KeyUp" />
+	<ul id="{{.IdComponent}}_list" role="listbox" style="position:absolute;list-style:none;margin:0;padding:0">{{.List}}</ul>
+</div>`
+}
+
+// List return the items of the suggestions
+func (t *Autocomplete) List() view.SafeHTML {
+	buf := new(bytes.Buffer)
+	err := view.Each(t.Suggestions, func(s string) view.Node {
+		return view.El("li", view.Attr("role", "option"), view.Attr("style", "cursor:pointer"),
+			view.Attr("lv-click", "Pick"), view.Attr("lv-value", s),
+			view.Text(s))
+	}).Render(buf)
+	if err != nil {
+		log.Println("Autocomplete:", err)
+	}
+	return view.SafeHTML(buf.String())
+}
+
+// KeyUp look for the suggestions of the text, only the list is rendered again so the
+// input keeps the focus
+func (t *Autocomplete) KeyUp(data interface{}) {
+	t.Value = fmt.Sprint(data)
+	t.Suggestions = nil
+	if t.Source != nil && len(t.Value) >= t.MinLength && t.Value != "" {
+		t.Suggestions = t.Source(t.Value)
+	}
+	t.FillValueById(t.IdComponent+"_list", string(t.List()))
+}
+
+// Pick is the click in a suggestion
+func (t *Autocomplete) Pick(data interface{}) {
+	t.Value = fmt.Sprint(data)
+	t.Suggestions = nil
+	t.Commit()
+	if t.onSelect != nil {
+		t.onSelect(t, t.Value)
+	}
+}
+
+func (t *Autocomplete) SetSource(fx func(text string) []string) *Autocomplete {
+	t.Source = fx
+	return t
+}
+
+func (t *Autocomplete) SetSelect(fx func(c *Autocomplete, value string)) *Autocomplete {
+	t.onSelect = fx
+	return t
+}
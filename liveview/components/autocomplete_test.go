@@ -0,0 +1,55 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+func TestAutocompleteKeyUp(t *testing.T) {
+	words := []string{"mango", "manzana", "melon", "<pera>"}
+	c := &Autocomplete{MinLength: 2}
+	view.NewDriver("auto", c)
+	looked := []string{}
+	c.SetSource(func(text string) []string {
+		looked = append(looked, text)
+		out := []string{}
+		for _, w := range words {
+			if strings.HasPrefix(w, text) {
+				out = append(out, w)
+			}
+		}
+		return out
+	})
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"m", nil},
+		{"ma", []string{"mango", "manzana"}},
+		{"man", []string{"mango", "manzana"}},
+		{"manz", []string{"manzana"}},
+		{"", nil},
+		{"<p", []string{"<pera>"}},
+	} {
+		c.KeyUp(tc.text)
+		if c.Value != tc.text || !reflect.DeepEqual(c.Suggestions, tc.want) {
+			t.Fatalf("KeyUp(%q) = %q %v, want %v", tc.text, c.Value, c.Suggestions, tc.want)
+		}
+	}
+	if want := []string{"ma", "man", "manz", "<p"}; !reflect.DeepEqual(looked, want) {
+		t.Fatalf("Source got %v, want %v, the texts shorter than MinLength are not looked for", looked, want)
+	}
+	if list := string(c.List()); strings.Contains(list, "<pera>") || !strings.Contains(list, "&lt;pera&gt;") {
+		t.Fatalf("the suggestions are not escaped: %s", list)
+	}
+
+	var picked string
+	c.SetSelect(func(c *Autocomplete, value string) { picked = value })
+	c.Pick("mango")
+	if c.Value != "mango" || picked != "mango" || c.Suggestions != nil {
+		t.Fatalf("Pick = %q, callback %q, suggestions %v", c.Value, picked, c.Suggestions)
+	}
+}
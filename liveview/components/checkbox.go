@@ -0,0 +1,42 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+type Checkbox struct {
+	*view.ComponentDriver[*Checkbox]
+	Label    string
+	Checked  bool
+	Disabled bool
+	onChange func(c *Checkbox, checked bool)
+}
+
+func (t *Checkbox) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Checkbox) Start() {
+	t.Commit()
+}
+
+func (t *Checkbox) GetTemplate() string {
+	return `<label><input type="checkbox" id="{{.IdComponent}}"
+	lv-change="Change"
+	{{if .Checked}}checked{{end}} {{if .Disabled}}disabled{{end}} /> {{.Label}}</label>`
+}
+
+// Change keep Checked as the checkbox of the browser
+func (t *Checkbox) Change(data interface{}) {
+	t.Checked = fmt.Sprint(data) == "true"
+	if t.onChange != nil {
+		t.onChange(t, t.Checked)
+	}
+}
+
+func (t *Checkbox) SetChange(fx func(c *Checkbox, checked bool)) *Checkbox {
+	t.onChange = fx
+	return t
+}
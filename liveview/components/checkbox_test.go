@@ -0,0 +1,29 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+func TestCheckboxChange(t *testing.T) {
+	c := &Checkbox{}
+	view.NewDriver("checkbox", c)
+	var got []bool
+	c.SetChange(func(c *Checkbox, checked bool) { got = append(got, checked) })
+	for _, tc := range []struct {
+		data interface{}
+		want bool
+	}{
+		{"true", true},
+		{"false", false},
+		{true, true},
+		{"on", false},
+		{nil, false},
+	} {
+		c.Change(tc.data)
+		if c.Checked != tc.want || got[len(got)-1] != tc.want {
+			t.Fatalf("Change(%v) = %v, callback %v, want %v", tc.data, c.Checked, got[len(got)-1], tc.want)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package components
 
 import (
-	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
 	"time"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
 )
 
 type Clock struct {
@@ -14,13 +15,10 @@ func (t *Clock) GetDriver() view.LiveDriver {
 	return t
 }
 func (t *Clock) Start() {
-	go func() {
-		for {
-			t.ActualTime = time.Now().Format(time.RFC3339Nano)
-			t.Commit()
-			time.Sleep((time.Second * 1) / 60)
-		}
-	}()
+	t.Every(time.Second/60, func() {
+		t.ActualTime = time.Now().Format(time.RFC3339Nano)
+		t.Commit()
+	})
 }
 
 func (t *Clock) GetTemplate() string {
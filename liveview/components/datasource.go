@@ -0,0 +1,104 @@
+package components
+
+import (
+	"sort"
+	"strings"
+)
+
+// Filter is the text of the filter of each column, by column key
+type Filter map[string]string
+
+// SortOrder is the column ordering the rows
+type SortOrder struct {
+	Key  string
+	Desc bool
+}
+
+// PageRequest is a page of rows, Number starts at 1. Size 0 is every row
+type PageRequest struct {
+	Number int
+	Size   int
+}
+
+// Offset return the position of the first row of the page
+func (p PageRequest) Offset() int {
+	if p.Number < 1 || p.Size <= 0 {
+		return 0
+	}
+	return (p.Number - 1) * p.Size
+}
+
+// DataSource give the rows of a Table, the filter, sort and paging are done by the source,
+// for example in a SQL query
+type DataSource[T any] interface {
+	Count(filter Filter) (int, error)
+	Fetch(filter Filter, sort SortOrder, page PageRequest) ([]T, error)
+}
+
+// SliceSource is a DataSource of rows in memory, a row match a filter when the text of
+// the column contains it, without caring about case
+type SliceSource[T any] struct {
+	Rows    []T
+	Columns []Column[T]
+}
+
+// NewSliceSource create a source of rows, the columns are used to filter and sort them
+func NewSliceSource[T any](rows []T, columns ...Column[T]) *SliceSource[T] {
+	return &SliceSource[T]{Rows: rows, Columns: columns}
+}
+
+func (s *SliceSource[T]) Count(filter Filter) (int, error) {
+	return len(s.filter(filter)), nil
+}
+
+func (s *SliceSource[T]) Fetch(filter Filter, order SortOrder, page PageRequest) ([]T, error) {
+	rows := s.filter(filter)
+	for _, col := range s.Columns {
+		if col.Key == order.Key {
+			less := col.less()
+			sort.SliceStable(rows, func(i, j int) bool {
+				if order.Desc {
+					return less(rows[j], rows[i])
+				}
+				return less(rows[i], rows[j])
+			})
+			break
+		}
+	}
+	if page.Size <= 0 {
+		return rows, nil
+	}
+	start := page.Offset()
+	if start >= len(rows) {
+		return []T{}, nil
+	}
+	end := start + page.Size
+	if end > len(rows) {
+		end = len(rows)
+	}
+	return rows[start:end], nil
+}
+
+// filter return a copy of the rows matching filter
+func (s *SliceSource[T]) filter(filter Filter) []T {
+	rows := make([]T, 0, len(s.Rows))
+	for _, row := range s.Rows {
+		if s.match(row, filter) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (s *SliceSource[T]) match(row T, filter Filter) bool {
+	for _, col := range s.Columns {
+		text := strings.TrimSpace(filter[col.Key])
+		if text == "" {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(col.text(row)), strings.ToLower(text)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package components
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type person struct {
+	ID   string
+	Name string
+	Age  int
+}
+
+var people = []person{
+	{"1", "Ana", 30},
+	{"2", "juan", 9},
+	{"3", "Mariana", 41},
+	{"4", "Pedro", 25},
+	{"5", "Luciana", 17},
+}
+
+func personColumns() []Column[person] {
+	return []Column[person]{
+		{Key: "name", Title: "Name", Value: func(p person) string { return p.Name }, Sortable: true, Filterable: true},
+		{Key: "age", Title: "Age", Value: func(p person) string { return strconv.Itoa(p.Age) }, Sortable: true,
+			Less: func(a, b person) bool { return a.Age < b.Age }},
+		{Key: "id", Title: "ID", Value: func(p person) string { return p.ID }},
+	}
+}
+
+func ids(rows []person) []string {
+	out := []string{}
+	for _, p := range rows {
+		out = append(out, p.ID)
+	}
+	return out
+}
+
+func TestSliceSource(t *testing.T) {
+	source := NewSliceSource(append([]person(nil), people...), personColumns()...)
+	for _, tc := range []struct {
+		name   string
+		filter Filter
+		order  SortOrder
+		page   PageRequest
+		want   []string
+		count  int
+	}{
+		{"every row", nil, SortOrder{}, PageRequest{}, []string{"1", "2", "3", "4", "5"}, 5},
+		{"filter without case", Filter{"name": "AN"}, SortOrder{}, PageRequest{}, []string{"1", "2", "3", "5"}, 4},
+		{"filter spaces", Filter{"name": "  "}, SortOrder{}, PageRequest{}, []string{"1", "2", "3", "4", "5"}, 5},
+		{"two filters", Filter{"name": "an", "age": "1"}, SortOrder{}, PageRequest{}, []string{"3", "5"}, 2},
+		{"no match", Filter{"name": "zz"}, SortOrder{}, PageRequest{}, []string{}, 0},
+		{"sort by value", nil, SortOrder{Key: "name"}, PageRequest{}, []string{"1", "5", "3", "4", "2"}, 5},
+		{"sort by less", nil, SortOrder{Key: "age"}, PageRequest{}, []string{"2", "5", "4", "1", "3"}, 5},
+		{"sort desc", nil, SortOrder{Key: "age", Desc: true}, PageRequest{}, []string{"3", "1", "4", "5", "2"}, 5},
+		{"sort unknown", nil, SortOrder{Key: "missing"}, PageRequest{}, []string{"1", "2", "3", "4", "5"}, 5},
+		{"first page", nil, SortOrder{}, PageRequest{Number: 1, Size: 2}, []string{"1", "2"}, 5},
+		{"last page", nil, SortOrder{}, PageRequest{Number: 3, Size: 2}, []string{"5"}, 5},
+		{"page out of range", nil, SortOrder{}, PageRequest{Number: 4, Size: 2}, []string{}, 5},
+		{"page zero", nil, SortOrder{}, PageRequest{Number: 0, Size: 2}, []string{"1", "2"}, 5},
+		{"filter, sort and page", Filter{"name": "an"}, SortOrder{Key: "age"}, PageRequest{Number: 2, Size: 2}, []string{"1", "3"}, 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := source.Fetch(tc.filter, tc.order, tc.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(rows); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Fetch = %v, want %v", got, tc.want)
+			}
+			if count, _ := source.Count(tc.filter); count != tc.count {
+				t.Fatalf("Count = %d, want %d", count, tc.count)
+			}
+		})
+	}
+	if got := ids(source.Rows); !reflect.DeepEqual(got, []string{"1", "2", "3", "4", "5"}) {
+		t.Fatalf("Fetch changed the order of Rows: %v", got)
+	}
+}
+
+func TestPageRequestOffset(t *testing.T) {
+	for _, tc := range []struct {
+		page PageRequest
+		want int
+	}{
+		{PageRequest{}, 0},
+		{PageRequest{Number: 1, Size: 10}, 0},
+		{PageRequest{Number: 3, Size: 10}, 20},
+		{PageRequest{Number: -1, Size: 10}, 0},
+		{PageRequest{Number: 3, Size: 0}, 0},
+	} {
+		if got := tc.page.Offset(); got != tc.want {
+			t.Errorf("%+v.Offset() = %d, want %d", tc.page, got, tc.want)
+		}
+	}
+}
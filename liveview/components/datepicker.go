@@ -0,0 +1,59 @@
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// DateLayout is the format of the dates of the browser
+const DateLayout = "2006-01-02"
+
+type DatePicker struct {
+	*view.ComponentDriver[*DatePicker]
+	// Value is zero when there is no date
+	Value    time.Time
+	Min      time.Time
+	Max      time.Time
+	onChange func(c *DatePicker, value time.Time)
+}
+
+func (t *DatePicker) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *DatePicker) Start() {
+	t.Commit()
+}
+
+func (t *DatePicker) GetTemplate() string {
+	return `<input type="date" id="{{.IdComponent}}" value="{{formatDate .Value}}"
+	{{if not .Min.IsZero}}min="{{formatDate .Min}}"{{end}} {{if not .Max.IsZero}}max="{{formatDate .Max}}"{{end}}
+	lv-change="Change" />`
+}
+
+// Change keep Value as the date of the browser, the dates out of Min and Max are ignored
+func (t *DatePicker) Change(data interface{}) {
+	value := time.Time{}
+	if s := fmt.Sprint(data); s != "" {
+		d, err := time.Parse(DateLayout, s)
+		if err != nil {
+			return
+		}
+		if (!t.Min.IsZero() && d.Before(t.Min)) || (!t.Max.IsZero() && d.After(t.Max)) {
+			t.Commit()
+			return
+		}
+		value = d
+	}
+	t.Value = value
+	if t.onChange != nil {
+		t.onChange(t, t.Value)
+	}
+}
+
+func (t *DatePicker) SetChange(fx func(c *DatePicker, value time.Time)) *DatePicker {
+	t.onChange = fx
+	return t
+}
@@ -0,0 +1,48 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+func TestDatePickerChange(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(DateLayout, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	c := &DatePicker{Min: date("2024-01-01"), Max: date("2024-12-31")}
+	view.NewDriver("date", c)
+	changes := 0
+	c.SetChange(func(c *DatePicker, value time.Time) { changes++ })
+	for _, tc := range []struct {
+		data    string
+		want    time.Time
+		changes int
+	}{
+		{"2024-05-10", date("2024-05-10"), 1},
+		{"2024-01-01", date("2024-01-01"), 2},
+		{"2023-12-31", date("2024-01-01"), 2},
+		{"2025-01-01", date("2024-01-01"), 2},
+		{"10/05/2024", date("2024-01-01"), 2},
+		{"2024-12-31", date("2024-12-31"), 3},
+		{"", time.Time{}, 4},
+	} {
+		c.Change(tc.data)
+		if !c.Value.Equal(tc.want) || changes != tc.changes {
+			t.Fatalf("Change(%q) = %v with %d changes, want %v with %d", tc.data, c.Value, changes, tc.want, tc.changes)
+		}
+	}
+
+	// without limits any date is taken
+	free := &DatePicker{}
+	view.NewDriver("free", free)
+	free.Change("1900-01-01")
+	if !free.Value.Equal(date("1900-01-01")) {
+		t.Fatalf("Change without limits = %v", free.Value)
+	}
+}
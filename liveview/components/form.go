@@ -0,0 +1,121 @@
+package components
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// Form edit Model, a struct with validate tags. The inputs of Fields are bound with
+// lv-model="Model.Name" and show the errors with {{.Error "Name"}}. The fields are
+// validated when they change and all of them on submit, the submit handler is invoked
+// only when the form is valid
+type Form[T any] struct {
+	*view.ComponentDriver[*Form[T]]
+	Model T
+	// Fields is the template of the inputs, rendered inside the form
+	Fields string
+	// Errors are the messages by field, the templates read them with Error and Invalid
+	Errors view.FieldErrors
+	// Submitted is true after the first submit, then the errors of every field are shown
+	Submitted bool
+	onSubmit  func(c *Form[T], model T)
+	// mu guard Errors and Submitted
+	mu sync.Mutex
+}
+
+// NewForm create the form id editing model with the inputs of fields, it fails when the
+// validate tags of the model have unknown rules or invalid params
+func NewForm[T any](id string, model T, fields string) (*Form[T], error) {
+	if err := view.CheckRules(&model); err != nil {
+		return nil, err
+	}
+	return view.New(id, &Form[T]{Model: model, Fields: fields}), nil
+}
+
+func (t *Form[T]) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Form[T]) Start() {
+	if err := view.CheckRules(&t.Model); err != nil {
+		log.Println("Form", t.IdComponent, err)
+	}
+	t.Commit()
+}
+
+func (t *Form[T]) GetTemplate() string {
+	return `<form id="{{.IdComponent}}" novalidate lv-submit="Submit">` +
+		t.Fields + `</form>`
+}
+
+// Error return the message of the field of Model, path is relative to Model
+func (t *Form[T]) Error(path string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.Errors[path]
+}
+
+// Invalid return true when the field of Model has an error
+func (t *Form[T]) Invalid(path string) bool {
+	return t.Error(path) != ""
+}
+
+// Valid return true when Model has no errors
+func (t *Form[T]) Valid() bool {
+	return len(view.Validate(&t.Model)) == 0
+}
+
+// ModelChanged validate the field changed, the form is rendered again when its error changes
+func (t *Form[T]) ModelChanged(field string) {
+	path, ok := strings.CutPrefix(field, "Model.")
+	if !ok {
+		return
+	}
+	message := view.ValidateField(&t.Model, path)
+	t.mu.Lock()
+	if message == t.Errors[path] {
+		t.mu.Unlock()
+		return
+	}
+	if t.Errors == nil {
+		t.Errors = view.FieldErrors{}
+	}
+	if message == "" {
+		delete(t.Errors, path)
+	} else {
+		t.Errors[path] = message
+	}
+	t.mu.Unlock()
+	t.Commit()
+}
+
+// Submit validate every field, the submit handler is invoked when there are no errors
+func (t *Form[T]) Submit(data interface{}) {
+	errs := view.Validate(&t.Model)
+	t.mu.Lock()
+	t.Submitted = true
+	t.Errors = errs
+	t.mu.Unlock()
+	t.Commit()
+	if len(errs) == 0 && t.onSubmit != nil {
+		t.onSubmit(t, t.Model)
+	}
+}
+
+// Reset replace the model edited and forget its errors
+func (t *Form[T]) Reset(model T) *Form[T] {
+	t.Model = model
+	t.mu.Lock()
+	t.Errors = nil
+	t.Submitted = false
+	t.mu.Unlock()
+	return t
+}
+
+func (t *Form[T]) SetSubmit(fx func(c *Form[T], model T)) *Form[T] {
+	t.onSubmit = fx
+	return t
+}
@@ -4,6 +4,8 @@ import "github.com/arturoeanton/go-fiber-live-view/liveview/view"
 
 type InputText struct {
 	*view.ComponentDriver[*InputText]
+	// Value is the text of the input, the browser keeps it updated
+	Value string
 }
 
 func (t *InputText) GetDriver() view.LiveDriver {
@@ -15,10 +17,8 @@ func (t *InputText) Start() {
 }
 
 func (t *InputText) GetTemplate() string {
-	return `<input type="text" 
-	onkeypress="send_event(this.id,'KeyPress',this.value)"
-	onchange="send_event(this.id,'Change',this.value)"
-	onkeyup="send_event(this.id,'KeyUp',this.value)"
+	return `<input type="text" lv-model="Value" value="{{.Value}}"
+	lv-keypress="KeyPress" lv-change="Change" lv-keyup="KeyUp"
 	id="{{.IdComponent}}"   />`
 }
 
@@ -0,0 +1,66 @@
+package components
+
+import (
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+type Modal struct {
+	*view.ComponentDriver[*Modal]
+	Title   string
+	Visible bool
+	// Content is the id of the component mounted in the body, see SetContent
+	Content   string
+	onDismiss func(c *Modal)
+}
+
+func (t *Modal) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Modal) Start() {
+	t.Commit()
+}
+
+// the dialog is hidden and not removed, so the content keeps its state
+func (t *Modal) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" class="modal" {{if not .Visible}}style="display:none"{{end}}>
+	<div class="modal-backdrop" style="position:fixed;inset:0;background:rgba(0,0,0,.4)"
+		lv-click="Dismiss"></div>
+	<div role="dialog" aria-modal="true" style="position:fixed;top:50%;left:50%;transform:translate(-50%,-50%);background:#fff;padding:1em">
+		<header>{{.Title}} <button aria-label="close" lv-click="Dismiss">&times;</button></header>
+		{{if .Content}}{{mount .Content}}{{end}}
+	</div>
+</div>`
+}
+
+// Show open the modal
+func (t *Modal) Show() {
+	t.Visible = true
+	t.Commit()
+}
+
+// Hide close the modal
+func (t *Modal) Hide() {
+	t.Visible = false
+	t.Commit()
+}
+
+// Dismiss is the click in the close button or out of the dialog
+func (t *Modal) Dismiss(data interface{}) {
+	t.Hide()
+	if t.onDismiss != nil {
+		t.onDismiss(t)
+	}
+}
+
+// SetContent mount the component content, created with view.NewDriver, in the body
+func (t *Modal) SetContent(content view.Component) *Modal {
+	t.Mount(content)
+	t.Content = content.GetDriver().GetIDComponet()
+	return t
+}
+
+func (t *Modal) SetDismiss(fx func(c *Modal)) *Modal {
+	t.onDismiss = fx
+	return t
+}
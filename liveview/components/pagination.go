@@ -0,0 +1,106 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// PageItem is a link of Pagination, Page 0 is a gap
+type PageItem struct {
+	Page    int
+	Current bool
+}
+
+type Pagination struct {
+	*view.ComponentDriver[*Pagination]
+	// Page is the current page, from 1 to Total
+	Page  int
+	Total int
+	// Window is the number of pages shown around the current one, 2 by default
+	Window   int
+	onChange func(c *Pagination, page int)
+}
+
+func (t *Pagination) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Pagination) Start() {
+	t.Commit()
+}
+
+func (t *Pagination) GetTemplate() string {
+	return `<nav id="{{.IdComponent}}" class="pagination" aria-label="pagination">
+	<button {{if le .Page 1}}disabled{{end}} lv-click="SelectPage" lv-value="{{.Previous}}">&laquo;</button>
+	{{range .Items}}{{if .Page}}<button {{if .Current}}aria-current="page" class="active"{{end}}
+		lv-click="SelectPage" lv-value="{{.Page}}">{{.Page}}</button>{{else}}<span>&hellip;</span>{{end}}{{end}}
+	<button {{if ge .Page .Total}}disabled{{end}} lv-click="SelectPage" lv-value="{{.Next}}">&raquo;</button>
+</nav>`
+}
+
+func (t *Pagination) Previous() int {
+	if t.Page <= 1 {
+		return 1
+	}
+	return t.Page - 1
+}
+
+func (t *Pagination) Next() int {
+	if t.Page >= t.Total {
+		return t.Total
+	}
+	return t.Page + 1
+}
+
+// Items return the first and last pages and the pages around the current one
+func (t *Pagination) Items() []PageItem {
+	window := t.Window
+	if window <= 0 {
+		window = 2
+	}
+	items := make([]PageItem, 0)
+	last := 0
+	for p := 1; p <= t.Total; p++ {
+		if p != 1 && p != t.Total && (p < t.Page-window || p > t.Page+window) {
+			continue
+		}
+		if last != 0 && p > last+1 {
+			items = append(items, PageItem{})
+		}
+		items = append(items, PageItem{Page: p, Current: p == t.Page})
+		last = p
+	}
+	return items
+}
+
+// SelectPage is the click in a page
+func (t *Pagination) SelectPage(data interface{}) {
+	page, err := strconv.Atoi(fmt.Sprint(data))
+	if err != nil || page < 1 || page > t.Total || page == t.Page {
+		return
+	}
+	t.Page = page
+	t.Commit()
+	if t.onChange != nil {
+		t.onChange(t, page)
+	}
+}
+
+// SetTotal set the number of pages, the current page is kept inside them
+func (t *Pagination) SetTotal(total int) *Pagination {
+	t.Total = total
+	if t.Page > total {
+		t.Page = total
+	}
+	if t.Page < 1 && total > 0 {
+		t.Page = 1
+	}
+	return t
+}
+
+func (t *Pagination) SetChange(fx func(c *Pagination, page int)) *Pagination {
+	t.onChange = fx
+	return t
+}
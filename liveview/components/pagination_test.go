@@ -0,0 +1,82 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+func TestPaginationSelectPage(t *testing.T) {
+	c := &Pagination{}
+	view.NewDriver("pages", c)
+	c.SetTotal(10)
+	var changes []int
+	c.SetChange(func(c *Pagination, page int) { changes = append(changes, page) })
+	for _, tc := range []struct {
+		data string
+		want int
+	}{
+		{"3", 3},
+		{"0", 3},
+		{"11", 3},
+		{"-1", 3},
+		{"x", 3},
+		{"3", 3},
+		{"10", 10},
+		{"1", 1},
+	} {
+		c.SelectPage(tc.data)
+		if c.Page != tc.want {
+			t.Fatalf("SelectPage(%q) = %d, want %d", tc.data, c.Page, tc.want)
+		}
+	}
+	if want := []int{3, 10, 1}; !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes %v, want %v", changes, want)
+	}
+	if c.Previous() != 1 || c.Next() != 2 {
+		t.Fatalf("previous %d next %d on the first page", c.Previous(), c.Next())
+	}
+	c.SetTotal(4)
+	c.SelectPage("4")
+	if c.Previous() != 3 || c.Next() != 4 {
+		t.Fatalf("previous %d next %d on the last page", c.Previous(), c.Next())
+	}
+}
+
+func TestPaginationSetTotal(t *testing.T) {
+	for _, tc := range []struct {
+		page, total, want int
+	}{
+		{0, 5, 1},
+		{7, 5, 5},
+		{3, 5, 3},
+		{0, 0, 0},
+	} {
+		c := &Pagination{Page: tc.page}
+		if c.SetTotal(tc.total); c.Page != tc.want {
+			t.Errorf("SetTotal(%d) from page %d = %d, want %d", tc.total, tc.page, c.Page, tc.want)
+		}
+	}
+}
+
+func TestPaginationItems(t *testing.T) {
+	page := func(p int) PageItem { return PageItem{Page: p} }
+	current := func(p int) PageItem { return PageItem{Page: p, Current: true} }
+	gap := PageItem{}
+	for _, tc := range []struct {
+		page, total, window int
+		want                []PageItem
+	}{
+		{1, 1, 0, []PageItem{current(1)}},
+		{1, 4, 0, []PageItem{current(1), page(2), page(3), page(4)}},
+		{5, 10, 0, []PageItem{page(1), gap, page(3), page(4), current(5), page(6), page(7), gap, page(10)}},
+		{10, 10, 1, []PageItem{page(1), gap, page(9), current(10)}},
+		{0, 0, 0, []PageItem{}},
+	} {
+		c := &Pagination{Page: tc.page, Total: tc.total, Window: tc.window}
+		if got := c.Items(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Items of page %d of %d = %v, want %v", tc.page, tc.total, got, tc.want)
+		}
+	}
+}
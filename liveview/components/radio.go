@@ -0,0 +1,48 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+type RadioGroup struct {
+	*view.ComponentDriver[*RadioGroup]
+	Options  []Option
+	Value    string
+	onChange func(c *RadioGroup, value string)
+}
+
+func (t *RadioGroup) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *RadioGroup) Start() {
+	t.Commit()
+}
+
+func (t *RadioGroup) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" role="radiogroup">
+	{{range .Options}}<label><input type="radio" name="{{$.IdComponent}}" value="{{.Value}}"
+		lv-change="Change"
+		{{if eq .Value $.Value}}checked{{end}} /> {{.Label}}</label>{{end}}
+</div>`
+}
+
+// Change keep Value as the option checked in the browser
+func (t *RadioGroup) Change(data interface{}) {
+	t.Value = fmt.Sprint(data)
+	if t.onChange != nil {
+		t.onChange(t, t.Value)
+	}
+}
+
+func (t *RadioGroup) SetOptions(options ...Option) *RadioGroup {
+	t.Options = options
+	return t
+}
+
+func (t *RadioGroup) SetChange(fx func(c *RadioGroup, value string)) *RadioGroup {
+	t.onChange = fx
+	return t
+}
@@ -0,0 +1,53 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// Option is a value with its label, used by Select and RadioGroup
+type Option struct {
+	Value string
+	Label string
+}
+
+type Select struct {
+	*view.ComponentDriver[*Select]
+	Options  []Option
+	Value    string
+	Disabled bool
+	onChange func(c *Select, value string)
+}
+
+func (t *Select) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Select) Start() {
+	t.Commit()
+}
+
+func (t *Select) GetTemplate() string {
+	return `<select id="{{.IdComponent}}" lv-change="Change" {{if .Disabled}}disabled{{end}}>
+	{{range .Options}}<option value="{{.Value}}" {{if eq .Value $.Value}}selected{{end}}>{{.Label}}</option>{{end}}
+</select>`
+}
+
+// Change keep Value as the option selected in the browser
+func (t *Select) Change(data interface{}) {
+	t.Value = fmt.Sprint(data)
+	if t.onChange != nil {
+		t.onChange(t, t.Value)
+	}
+}
+
+func (t *Select) SetOptions(options ...Option) *Select {
+	t.Options = options
+	return t
+}
+
+func (t *Select) SetChange(fx func(c *Select, value string)) *Select {
+	t.onChange = fx
+	return t
+}
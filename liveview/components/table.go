@@ -0,0 +1,609 @@
+package components
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// Column is a column of a Table, Value is the text of the cell of a row
+type Column[T any] struct {
+	Key      string
+	Title    string
+	Value    func(row T) string
+	Sortable bool
+	// Filterable show an input to filter the rows by the column
+	Filterable bool
+	// Less order the rows by the column, without it they are ordered by Value
+	Less func(a, b T) bool
+}
+
+// RowAction is a button of each row, its click invoke the function set with SetAction
+type RowAction struct {
+	Name  string
+	Label string
+}
+
+type tableHeader struct {
+	Key        string
+	Title      string
+	Sortable   bool
+	Sorted     string
+	Filterable bool
+	Filter     string
+}
+
+type renderedRow struct {
+	key  string
+	html string
+}
+
+// Table show rows in columns. The rows are Rows or the ones given by Source, which does the
+// sorting, filtering and paging. When the rows change Refresh update only the changed ones
+type Table[T any] struct {
+	*view.ComponentDriver[*Table[T]]
+	Columns []Column[T]
+	// Rows are the rows of the table when it has no Source
+	Rows     []T
+	Source   DataSource[T]
+	SortKey  string
+	SortDesc bool
+	Filters  Filter
+	// PageSize is the number of rows of a page, 0 show every row
+	PageSize int
+	// Page is the page shown, from 1, and Total the number of rows of every page
+	Page  int
+	Total int
+	// Key identify a row for the selection, the actions and the updates. Without it a row
+	// is identified by its position
+	Key        func(row T) string
+	Selectable bool
+	Selected   map[string]bool
+	Actions    []RowAction
+
+	// mu guard the state of the page, the template only reads what is prepared under it
+	mu       sync.Mutex
+	shown    []T
+	rendered []renderedRow
+	pager    string
+	onSort   func(c *Table[T], key string, desc bool)
+	onSelect func(c *Table[T], keys []string)
+	actions  map[string]func(c *Table[T], row T)
+}
+
+func (t *Table[T]) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Table[T]) Start() {
+	t.mu.Lock()
+	t.load()
+	t.prepare()
+	t.mu.Unlock()
+	t.Commit()
+}
+
+func (t *Table[T]) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" class="table">
+<table>
+	<thead><tr>{{if .Selectable}}<th><input type="checkbox" {{if .AllSelected}}checked{{end}}
+		lv-change="SelectAll" /></th>{{end}}
+	{{range .Headers}}
+		{{if .Sortable}}<th style="cursor:pointer" lv-click="Sort" lv-value="{{.Key}}">{{.Title}} {{.Sorted}}</th>
+		{{else}}<th>{{.Title}}</th>{{end}}
+	{{end}}{{if .Actions}}<th></th>{{end}}</tr>
+	{{if .Filterable}}<tr class="filters">{{if .Selectable}}<th></th>{{end}}{{range .Headers}}<th>{{if .Filterable}}<input type="search" value="{{.Filter}}"
+		lv-keyup="Filter" lv-value="{{.Key}}" />{{end}}</th>{{end}}{{if .Actions}}<th></th>{{end}}</tr>{{end}}
+	</thead>
+	<tbody id="{{.IdComponent}}_body">{{.Body}}</tbody>
+</table>
+<div id="{{.IdComponent}}_pager" class="pager">{{.Pager}}</div>
+</div>`
+}
+
+// Headers return the headers of the columns, with the arrow of the sorted one
+func (t *Table[T]) Headers() []tableHeader {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	headers := make([]tableHeader, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		h := tableHeader{Key: col.Key, Title: col.Title, Sortable: col.Sortable, Filterable: col.Filterable, Filter: t.Filters[col.Key]}
+		if col.Key == t.SortKey {
+			h.Sorted = "▲"
+			if t.SortDesc {
+				h.Sorted = "▼"
+			}
+		}
+		headers = append(headers, h)
+	}
+	return headers
+}
+
+// Filterable return true when a column can be filtered
+func (t *Table[T]) Filterable() bool {
+	for _, col := range t.Columns {
+		if col.Filterable {
+			return true
+		}
+	}
+	return false
+}
+
+// AllSelected return true when every row shown is selected
+func (t *Table[T]) AllSelected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.allSelected()
+}
+
+func (t *Table[T]) allSelected() bool {
+	if len(t.shown) == 0 {
+		return false
+	}
+	for i, row := range t.shown {
+		if !t.Selected[t.rowKey(i, row)] {
+			return false
+		}
+	}
+	return true
+}
+
+// Cells return the text of the cells of each row shown
+func (t *Table[T]) Cells() [][]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cells := make([][]string, 0, len(t.shown))
+	for _, row := range t.shown {
+		cells = append(cells, t.rowCells(row))
+	}
+	return cells
+}
+
+func (t *Table[T]) rowCells(row T) []string {
+	out := make([]string, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		out = append(out, col.text(row))
+	}
+	return out
+}
+
+func (col Column[T]) text(row T) string {
+	if col.Value == nil {
+		return fmt.Sprint(row)
+	}
+	return col.Value(row)
+}
+
+func (col Column[T]) less() func(a, b T) bool {
+	if col.Less != nil {
+		return col.Less
+	}
+	return func(a, b T) bool { return col.text(a) < col.text(b) }
+}
+
+func (t *Table[T]) column(key string) (Column[T], bool) {
+	for _, col := range t.Columns {
+		if col.Key == key {
+			return col, true
+		}
+	}
+	return Column[T]{}, false
+}
+
+// Body return the rows shown, as they were prepared. The template does not change the
+// table, a Commit from outside of its methods (a parent, CommitChangedTemplates) does not
+// hold mu while it renders
+func (t *Table[T]) Body() view.SafeHTML {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return view.SafeHTML(t.joinRows(t.rendered))
+}
+
+// Pager return the links to the previous and next pages, as they were prepared
+func (t *Table[T]) Pager() view.SafeHTML {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return view.SafeHTML(t.pager)
+}
+
+// prepare render the rows and the pager of the page for the next Commit, the caller
+// holds mu
+func (t *Table[T]) prepare() {
+	t.rendered = t.renderRows()
+	t.pager = t.renderPager()
+}
+
+func (t *Table[T]) source() DataSource[T] {
+	if t.Source != nil {
+		return t.Source
+	}
+	return NewSliceSource(t.Rows, t.Columns...)
+}
+
+func (t *Table[T]) pages() int {
+	if t.PageSize <= 0 {
+		return 1
+	}
+	pages := (t.Total + t.PageSize - 1) / t.PageSize
+	if pages < 1 {
+		pages = 1
+	}
+	return pages
+}
+
+// load fetch the rows of the page, the errors of the source are logged and the rows shown kept
+func (t *Table[T]) load() {
+	source := t.source()
+	total, err := source.Count(t.Filters)
+	if err != nil {
+		log.Println("Table", t.IdComponent, err)
+		return
+	}
+	t.Total = total
+	if t.Page < 1 {
+		t.Page = 1
+	}
+	if pages := t.pages(); t.Page > pages {
+		t.Page = pages
+	}
+	rows, err := source.Fetch(t.Filters, SortOrder{Key: t.SortKey, Desc: t.SortDesc}, PageRequest{Number: t.Page, Size: t.PageSize})
+	if err != nil {
+		log.Println("Table", t.IdComponent, err)
+		return
+	}
+	t.shown = rows
+}
+
+// Refresh fetch the rows again, only the rows that changed are rendered again
+func (t *Table[T]) Refresh() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.load()
+	t.update()
+}
+
+// update send the rows that changed since the last render and the pager
+func (t *Table[T]) update() {
+	old := t.rendered
+	rows := t.renderRows()
+	t.rendered = rows
+	if sameKeys(old, rows) {
+		for i, row := range rows {
+			if row.html != old[i].html {
+				t.FillValueById(t.rowID(row.key), row.html)
+			}
+		}
+	} else {
+		t.FillValueById(t.IdComponent+"_body", t.joinRows(rows))
+	}
+	if pager := t.renderPager(); pager != t.pager {
+		t.pager = pager
+		t.FillValueById(t.IdComponent+"_pager", pager)
+	}
+}
+
+func sameKeys(a, b []renderedRow) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].key != b[i].key {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Table[T]) rowKey(i int, row T) string {
+	if t.Key != nil {
+		return t.Key(row)
+	}
+	return strconv.Itoa(PageRequest{Number: t.Page, Size: t.PageSize}.Offset() + i)
+}
+
+func (t *Table[T]) rowID(key string) string {
+	return t.IdComponent + "_row_" + url.QueryEscape(key)
+}
+
+// joinRows return the html of the rows with their tr
+func (t *Table[T]) joinRows(rows []renderedRow) string {
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.WriteString(`<tr id="` + html.EscapeString(t.rowID(row.key)) + `">` + row.html + "</tr>")
+	}
+	return sb.String()
+}
+
+// renderRows return the html of the cells of each row shown, fill replace the content of
+// the tr so the html is kept without it
+func (t *Table[T]) renderRows() []renderedRow {
+	rows := make([]renderedRow, 0, len(t.shown))
+	for i, row := range t.shown {
+		key := t.rowKey(i, row)
+		rows = append(rows, renderedRow{key: key, html: renderNode(t.rowNode(key, row))})
+	}
+	return rows
+}
+
+func (t *Table[T]) rowNode(key string, row T) view.Node {
+	cells := make([]view.Node, 0, len(t.Columns)+2)
+	if t.Selectable {
+		cells = append(cells, view.El("td", view.El("input", view.Attr("type", "checkbox"),
+			view.BoolAttr("checked", t.Selected[key]), view.Attr("lv-change", "SelectRow"), view.Attr("lv-value", key))))
+	}
+	for _, text := range t.rowCells(row) {
+		cells = append(cells, view.El("td", view.Text(text)))
+	}
+	if len(t.Actions) > 0 {
+		cells = append(cells, view.El("td", view.Each(t.Actions, func(a RowAction) view.Node {
+			return view.El("button", view.Attr("lv-click", "Action"), view.Attr("lv-value", a.Name+":"+key),
+				view.Text(a.Label))
+		})))
+	}
+	return view.Group(cells...)
+}
+
+func (t *Table[T]) renderPager() string {
+	if t.PageSize <= 0 {
+		return ""
+	}
+	pages := t.pages()
+	return renderNode(view.Group(
+		view.El("button", view.BoolAttr("disabled", t.Page <= 1),
+			view.Attr("lv-click", "SelectPage"), view.Attr("lv-value", strconv.Itoa(t.Page-1)),
+			view.Raw("&laquo;")),
+		view.El("span", view.Text(fmt.Sprintf(" %d / %d (%d) ", t.Page, pages, t.Total))),
+		view.El("button", view.BoolAttr("disabled", t.Page >= pages),
+			view.Attr("lv-click", "SelectPage"), view.Attr("lv-value", strconv.Itoa(t.Page+1)),
+			view.Raw("&raquo;")),
+	))
+}
+
+func renderNode(n view.Node) string {
+	buf := new(bytes.Buffer)
+	if err := n.Render(buf); err != nil {
+		log.Println("Table:", err)
+	}
+	return buf.String()
+}
+
+// Sort order the rows by the column clicked, clicking it again reverse the order
+func (t *Table[T]) Sort(data interface{}) {
+	key := fmt.Sprint(data)
+	col, ok := t.column(key)
+	if !ok || !col.Sortable {
+		return
+	}
+	t.mu.Lock()
+	if t.SortKey == key {
+		t.SortDesc = !t.SortDesc
+	} else {
+		t.SortKey = key
+		t.SortDesc = false
+	}
+	desc := t.SortDesc
+	t.mu.Unlock()
+	if t.onSort != nil {
+		t.onSort(t, key, desc)
+	}
+	t.mu.Lock()
+	t.load()
+	t.prepare()
+	t.mu.Unlock()
+	t.Commit()
+}
+
+// Filter is the text written in the filter of a column, data is "key:text"
+func (t *Table[T]) Filter(data interface{}) {
+	key, text, ok := strings.Cut(fmt.Sprint(data), ":")
+	if !ok {
+		return
+	}
+	if col, ok := t.column(key); !ok || !col.Filterable {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Filters == nil {
+		t.Filters = make(Filter)
+	}
+	if t.Filters[key] == text {
+		return
+	}
+	t.Filters[key] = text
+	t.Page = 1
+	t.load()
+	t.update()
+}
+
+// SelectPage is the click in the pager
+func (t *Table[T]) SelectPage(data interface{}) {
+	page, err := strconv.Atoi(fmt.Sprint(data))
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if page < 1 || page > t.pages() || page == t.Page {
+		return
+	}
+	t.Page = page
+	t.load()
+	t.update()
+}
+
+// SelectRow is the checkbox of a row, data is "key:checked"
+func (t *Table[T]) SelectRow(data interface{}) {
+	text := fmt.Sprint(data)
+	i := strings.LastIndex(text, ":")
+	if i < 0 {
+		return
+	}
+	key := text[:i]
+	checked := text[i+1:] == "true"
+	t.mu.Lock()
+	if t.Selected == nil {
+		t.Selected = make(map[string]bool)
+	}
+	all := t.allSelected()
+	if checked {
+		t.Selected[key] = true
+	} else {
+		delete(t.Selected, key)
+	}
+	// the checkbox of the header is in the template, it is rendered again when it changes
+	header := all != t.allSelected()
+	if header {
+		t.prepare()
+	} else {
+		t.update()
+	}
+	keys := t.selectedKeys()
+	t.mu.Unlock()
+	if header {
+		t.Commit()
+	}
+	if t.onSelect != nil {
+		t.onSelect(t, keys)
+	}
+}
+
+// SelectAll is the checkbox of the header, it selects every row shown, data is "true"
+// or "false"
+func (t *Table[T]) SelectAll(data interface{}) {
+	checked := fmt.Sprint(data) == "true"
+	t.mu.Lock()
+	if t.Selected == nil {
+		t.Selected = make(map[string]bool)
+	}
+	for i, row := range t.shown {
+		if checked {
+			t.Selected[t.rowKey(i, row)] = true
+		} else {
+			delete(t.Selected, t.rowKey(i, row))
+		}
+	}
+	t.prepare()
+	keys := t.selectedKeys()
+	t.mu.Unlock()
+	t.Commit()
+	if t.onSelect != nil {
+		t.onSelect(t, keys)
+	}
+}
+
+// SelectedKeys return the keys of the rows selected
+func (t *Table[T]) SelectedKeys() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.selectedKeys()
+}
+
+func (t *Table[T]) selectedKeys() []string {
+	keys := make([]string, 0, len(t.Selected))
+	for key, ok := range t.Selected {
+		if ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Action is the click in the button of a row, data is "name:key"
+func (t *Table[T]) Action(data interface{}) {
+	name, key, ok := strings.Cut(fmt.Sprint(data), ":")
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	fx := t.actions[name]
+	var row T
+	found := false
+	for i, r := range t.shown {
+		if t.rowKey(i, r) == key {
+			row, found = r, true
+			break
+		}
+	}
+	t.mu.Unlock()
+	if fx != nil && found {
+		fx(t, row)
+	}
+}
+
+// SortRows order Rows by SortKey
+func (t *Table[T]) SortRows() {
+	col, ok := t.column(t.SortKey)
+	if !ok {
+		return
+	}
+	less := col.less()
+	sort.SliceStable(t.Rows, func(i, j int) bool {
+		if t.SortDesc {
+			return less(t.Rows[j], t.Rows[i])
+		}
+		return less(t.Rows[i], t.Rows[j])
+	})
+}
+
+func (t *Table[T]) SetColumns(columns ...Column[T]) *Table[T] {
+	t.Columns = columns
+	return t
+}
+
+// SetRows set the rows of the table without Source, they are copied so SortRows does not
+// change the order of rows
+func (t *Table[T]) SetRows(rows []T) *Table[T] {
+	t.Rows = append([]T(nil), rows...)
+	t.SortRows()
+	return t
+}
+
+// SetSource set the source of the rows, see DataSource
+func (t *Table[T]) SetSource(source DataSource[T]) *Table[T] {
+	t.Source = source
+	return t
+}
+
+func (t *Table[T]) SetPageSize(size int) *Table[T] {
+	t.PageSize = size
+	return t
+}
+
+// SetKey set the function identifying the rows
+func (t *Table[T]) SetKey(fx func(row T) string) *Table[T] {
+	t.Key = fx
+	return t
+}
+
+// SetSort is invoked when the order changes, before the rows are fetched again
+func (t *Table[T]) SetSort(fx func(c *Table[T], key string, desc bool)) *Table[T] {
+	t.onSort = fx
+	return t
+}
+
+// SetSelect show a checkbox in each row, fx is invoked with the keys selected
+func (t *Table[T]) SetSelect(fx func(c *Table[T], keys []string)) *Table[T] {
+	t.Selectable = true
+	t.onSelect = fx
+	return t
+}
+
+// SetAction add a button to each row, fx is invoked with the row clicked
+func (t *Table[T]) SetAction(name string, label string, fx func(c *Table[T], row T)) *Table[T] {
+	if t.actions == nil {
+		t.actions = make(map[string]func(c *Table[T], row T))
+	}
+	t.actions[name] = fx
+	t.Actions = append(t.Actions, RowAction{Name: name, Label: label})
+	return t
+}
@@ -0,0 +1,202 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// newPeopleTable return a started table of people by ID with pages of size, without
+// connection the messages of the table are dropped
+func newPeopleTable(size int) *Table[person] {
+	t := &Table[person]{}
+	view.NewDriver("people", t)
+	t.SetColumns(personColumns()...).SetRows(people).SetPageSize(size)
+	t.SetKey(func(p person) string { return p.ID })
+	t.Start()
+	return t
+}
+
+func TestTableSetRows(t *testing.T) {
+	rows := append([]person(nil), people...)
+	table := &Table[person]{SortKey: "age"}
+	table.SetColumns(personColumns()...).SetRows(rows)
+	if got := ids(rows); !reflect.DeepEqual(got, ids(people)) {
+		t.Fatalf("SetRows sorted the slice of the caller: %v", got)
+	}
+	if got := ids(table.Rows); !reflect.DeepEqual(got, []string{"2", "5", "4", "1", "3"}) {
+		t.Fatalf("Rows = %v, want them sorted by age", got)
+	}
+}
+
+func TestTableSort(t *testing.T) {
+	table := newPeopleTable(0)
+	for _, tc := range []struct {
+		key  string
+		want []string
+		desc bool
+	}{
+		{"name", []string{"1", "5", "3", "4", "2"}, false},
+		{"name", []string{"2", "4", "3", "5", "1"}, true},
+		{"age", []string{"2", "5", "4", "1", "3"}, false},
+		{"id", []string{"2", "5", "4", "1", "3"}, false},
+		{"missing", []string{"2", "5", "4", "1", "3"}, false},
+	} {
+		table.Sort(tc.key)
+		if got := ids(table.shown); !reflect.DeepEqual(got, tc.want) || table.SortDesc != tc.desc {
+			t.Fatalf("Sort(%q) = %v desc %v, want %v desc %v", tc.key, got, table.SortDesc, tc.want, tc.desc)
+		}
+	}
+	if h := table.Headers()[1]; h.Sorted != "▲" {
+		t.Fatalf("header of age %+v", h)
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	table := newPeopleTable(2)
+	table.SelectPage("2")
+	for _, tc := range []struct {
+		data string
+		want []string
+	}{
+		{"name:an", []string{"1", "2"}},
+		{"name", []string{"1", "2"}},
+		{"id:1", []string{"1", "2"}},
+		{"missing:1", []string{"1", "2"}},
+		{"name:ped", []string{"4"}},
+		{"name:a:b", []string{}},
+		{"name:", []string{"1", "2"}},
+	} {
+		table.Filter(tc.data)
+		if got := ids(table.shown); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Filter(%q) = %v, want %v", tc.data, got, tc.want)
+		}
+		if table.Page != 1 {
+			t.Fatalf("Filter(%q) kept the page %d", tc.data, table.Page)
+		}
+	}
+	if table.Filters["name"] != "" || len(table.Filters) != 1 {
+		t.Fatalf("filters %v", table.Filters)
+	}
+}
+
+func TestTableSelectPage(t *testing.T) {
+	table := newPeopleTable(2)
+	for _, tc := range []struct {
+		data string
+		page int
+		want []string
+	}{
+		{"2", 2, []string{"3", "4"}},
+		{"3", 3, []string{"5"}},
+		{"4", 3, []string{"5"}},
+		{"0", 3, []string{"5"}},
+		{"x", 3, []string{"5"}},
+		{"1", 1, []string{"1", "2"}},
+	} {
+		table.SelectPage(tc.data)
+		if got := ids(table.shown); table.Page != tc.page || !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("SelectPage(%q) = page %d %v, want %d %v", tc.data, table.Page, got, tc.page, tc.want)
+		}
+	}
+	if pager := string(table.Pager()); !strings.Contains(pager, " 1 / 3 (5) ") {
+		t.Fatalf("pager %s", pager)
+	}
+
+	// the rows removed from the source move the page back
+	table.SelectPage("3")
+	table.Rows = table.Rows[:2]
+	table.Refresh()
+	if table.Page != 1 || table.Total != 2 {
+		t.Fatalf("Refresh kept the page %d of %d rows", table.Page, table.Total)
+	}
+}
+
+func TestTableUpdate(t *testing.T) {
+	table := newPeopleTable(2)
+	before := append([]renderedRow(nil), table.rendered...)
+	table.Rows[1].Name = "Juan Pablo"
+	table.Refresh()
+	if table.rendered[0].html != before[0].html {
+		t.Fatal("the row not changed was rendered again")
+	}
+	if table.rendered[1].html == before[1].html || !strings.Contains(table.rendered[1].html, "Juan Pablo") {
+		t.Fatalf("the row changed was not rendered again: %s", table.rendered[1].html)
+	}
+	if !sameKeys(before, table.rendered) {
+		t.Fatal("the keys of the page changed")
+	}
+	if sameKeys(before, before[:1]) || sameKeys(before, []renderedRow{before[1], before[0]}) {
+		t.Fatal("sameKeys ignores the length or the order")
+	}
+}
+
+func TestTableSelect(t *testing.T) {
+	table := newPeopleTable(2)
+	var selected []string
+	table.SetSelect(func(c *Table[person], keys []string) { selected = keys })
+	for _, tc := range []struct {
+		handler string
+		data    string
+		want    []string
+		all     bool
+	}{
+		{"row", "2:true", []string{"2"}, false},
+		{"row", "1:true", []string{"1", "2"}, true},
+		{"row", "1:false", []string{"2"}, false},
+		{"row", "nothing", []string{"2"}, false},
+		{"all", "true", []string{"1", "2"}, true},
+		{"all", "false", []string{}, false},
+	} {
+		if tc.handler == "row" {
+			table.SelectRow(tc.data)
+		} else {
+			table.SelectAll(tc.data)
+		}
+		if got := table.SelectedKeys(); !reflect.DeepEqual(got, tc.want) || table.AllSelected() != tc.all {
+			t.Fatalf("%s %q: selected %v all %v, want %v all %v", tc.handler, tc.data, got, table.AllSelected(), tc.want, tc.all)
+		}
+		if tc.data != "nothing" && !reflect.DeepEqual(selected, tc.want) {
+			t.Fatalf("%s %q: the callback got %v, want %v", tc.handler, tc.data, selected, tc.want)
+		}
+	}
+
+	// SelectAll only selects the rows of the page
+	table.SelectPage("2")
+	table.SelectAll("true")
+	if got := table.SelectedKeys(); !reflect.DeepEqual(got, []string{"3", "4"}) {
+		t.Fatalf("selected %v, want the rows of the page", got)
+	}
+	// the keys can have ":"
+	table.SetKey(func(p person) string { return "p:" + p.ID })
+	table.Refresh()
+	table.SelectRow("p:3:true")
+	if !table.Selected["p:3"] {
+		t.Fatalf("selected %v, want p:3", table.Selected)
+	}
+}
+
+func TestTableAction(t *testing.T) {
+	table := newPeopleTable(2)
+	var got []string
+	table.SetAction("edit", "Edit", func(c *Table[person], row person) { got = append(got, "edit "+row.ID) })
+	table.SetAction("delete", "Delete", func(c *Table[person], row person) { got = append(got, "delete "+row.ID) })
+	for _, data := range []string{"edit:2", "delete:1", "edit:3", "missing:1", "edit", "edit:"} {
+		table.Action(data)
+	}
+	if want := []string{"edit 2", "delete 1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("actions %v, want %v, only the rows shown", got, want)
+	}
+
+	// without Key the rows are identified by their position in every page
+	table.Key = nil
+	table.SelectPage("2")
+	got = nil
+	table.Action("edit:2")
+	table.Action("edit:0")
+	if want := []string{"edit 3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("actions %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// Tab is a tab of Tabs, Content is the id of the component mounted in the tab
+type Tab struct {
+	Title   string
+	Content string
+}
+
+type Tabs struct {
+	*view.ComponentDriver[*Tabs]
+	Tabs     []Tab
+	Active   int
+	onChange func(c *Tabs, active int)
+}
+
+func (t *Tabs) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Tabs) Start() {
+	t.Commit()
+}
+
+// the content of every tab is mounted, the inactive ones are hidden so they keep their state
+func (t *Tabs) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" class="tabs">
+	<div role="tablist">{{range $i, $tab := .Tabs}}
+		<button role="tab" aria-selected="{{eqInt $i $.Active}}" {{if eqInt $i $.Active}}class="active"{{end}}
+			lv-click="Select" lv-value="{{$i}}">{{$tab.Title}}</button>
+	{{end}}</div>
+	{{range $i, $tab := .Tabs}}<div role="tabpanel" {{if not (eqInt $i $.Active)}}style="display:none"{{end}}>{{mount $tab.Content}}</div>{{end}}
+</div>`
+}
+
+// Select activate the tab clicked
+func (t *Tabs) Select(data interface{}) {
+	i, err := strconv.Atoi(fmt.Sprint(data))
+	if err != nil || i < 0 || i >= len(t.Tabs) {
+		return
+	}
+	t.SetActive(i)
+	t.Commit()
+	if t.onChange != nil {
+		t.onChange(t, i)
+	}
+}
+
+// AddTab add a tab with the component content, created with view.NewDriver. The content
+// is a child of the tabs, so it is rendered again with them
+func (t *Tabs) AddTab(title string, content view.Component) *Tabs {
+	t.Mount(content)
+	t.Tabs = append(t.Tabs, Tab{Title: title, Content: content.GetDriver().GetIDComponet()})
+	return t
+}
+
+func (t *Tabs) SetActive(i int) *Tabs {
+	t.Active = i
+	return t
+}
+
+func (t *Tabs) SetChange(fx func(c *Tabs, active int)) *Tabs {
+	t.onChange = fx
+	return t
+}
@@ -0,0 +1,32 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+func TestTabsSelect(t *testing.T) {
+	c := &Tabs{Tabs: []Tab{{Title: "a"}, {Title: "b"}, {Title: "c"}}}
+	view.NewDriver("tabs", c)
+	var changes []int
+	c.SetChange(func(c *Tabs, active int) { changes = append(changes, active) })
+	for _, tc := range []struct {
+		data string
+		want int
+	}{
+		{"2", 2},
+		{"3", 2},
+		{"-1", 2},
+		{"x", 2},
+		{"0", 0},
+	} {
+		c.Select(tc.data)
+		if c.Active != tc.want {
+			t.Fatalf("Select(%q) = %d, want %d", tc.data, c.Active, tc.want)
+		}
+	}
+	if len(changes) != 2 || changes[0] != 2 || changes[1] != 0 {
+		t.Fatalf("changes %v", changes)
+	}
+}
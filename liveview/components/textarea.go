@@ -0,0 +1,54 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+type TextArea struct {
+	*view.ComponentDriver[*TextArea]
+	Value       string
+	Rows        int
+	Placeholder string
+	onChange    func(c *TextArea, value string)
+	onKeyUp     func(c *TextArea, value string)
+}
+
+func (t *TextArea) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *TextArea) Start() {
+	t.Commit()
+}
+
+func (t *TextArea) GetTemplate() string {
+	return `<textarea id="{{.IdComponent}}" {{if .Rows}}rows="{{.Rows}}"{{end}} placeholder="{{.Placeholder}}"
+	lv-change="Change" lv-keyup="KeyUp">{{.Value}}</textarea>`
+}
+
+// Change keep Value as the text of the browser
+func (t *TextArea) Change(data interface{}) {
+	t.Value = fmt.Sprint(data)
+	if t.onChange != nil {
+		t.onChange(t, t.Value)
+	}
+}
+
+func (t *TextArea) KeyUp(data interface{}) {
+	t.Value = fmt.Sprint(data)
+	if t.onKeyUp != nil {
+		t.onKeyUp(t, t.Value)
+	}
+}
+
+func (t *TextArea) SetChange(fx func(c *TextArea, value string)) *TextArea {
+	t.onChange = fx
+	return t
+}
+
+func (t *TextArea) SetKeyUp(fx func(c *TextArea, value string)) *TextArea {
+	t.onKeyUp = fx
+	return t
+}
@@ -0,0 +1,104 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+// ToastMessage is a message shown by Toast
+type ToastMessage struct {
+	ID   int
+	Text string
+	// Kind is the class of the message, for example info, success, warning or error
+	Kind string
+}
+
+type Toast struct {
+	*view.ComponentDriver[*Toast]
+	// Duration is the time a message is shown, 5 seconds by default
+	Duration time.Duration
+
+	mu       sync.Mutex
+	messages []ToastMessage
+	nextID   int
+}
+
+func (t *Toast) GetDriver() view.LiveDriver {
+	return t
+}
+
+func (t *Toast) Start() {
+	t.Commit()
+}
+
+func (t *Toast) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" class="toast" aria-live="polite" style="position:fixed;right:1em;bottom:1em">
+	{{range .Messages}}<div class="toast-message {{.Kind}}">{{.Text}}
+		<button aria-label="close" lv-click="Dismiss" lv-value="{{.ID}}">&times;</button></div>{{end}}
+</div>`
+}
+
+// Messages return the messages shown
+func (t *Toast) Messages() []ToastMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ToastMessage(nil), t.messages...)
+}
+
+// Show add a message, it is removed after Duration
+func (t *Toast) Show(kind string, text string) {
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.messages = append(t.messages, ToastMessage{ID: id, Text: text, Kind: kind})
+	t.mu.Unlock()
+	t.Commit()
+
+	duration := t.Duration
+	if duration <= 0 {
+		duration = 5 * time.Second
+	}
+	t.Go(func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(duration):
+			t.remove(id)
+		}
+	})
+}
+
+func (t *Toast) Info(text string) {
+	t.Show("info", text)
+}
+
+func (t *Toast) Error(text string) {
+	t.Show("error", text)
+}
+
+// Dismiss is the click in the close button of a message
+func (t *Toast) Dismiss(data interface{}) {
+	var id int
+	if _, err := fmt.Sscan(fmt.Sprint(data), &id); err == nil {
+		t.remove(id)
+	}
+}
+
+func (t *Toast) remove(id int) {
+	t.mu.Lock()
+	found := false
+	for i, m := range t.messages {
+		if m.ID == id {
+			t.messages = append(t.messages[:i], t.messages[i+1:]...)
+			found = true
+			break
+		}
+	}
+	t.mu.Unlock()
+	if found {
+		t.Commit()
+	}
+}
@@ -0,0 +1,59 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/view"
+)
+
+func toastTexts(c *Toast) []string {
+	out := []string{}
+	for _, m := range c.Messages() {
+		out = append(out, m.Kind+":"+m.Text)
+	}
+	return out
+}
+
+func TestToastDismiss(t *testing.T) {
+	c := &Toast{Duration: time.Hour}
+	view.NewDriver("toast", c)
+	c.Info("one")
+	c.Error("two")
+	c.Show("success", "three")
+	for _, data := range []interface{}{"x", "", 9, "2"} {
+		c.Dismiss(data)
+	}
+	if got := toastTexts(c); len(got) != 2 || got[0] != "info:one" || got[1] != "success:three" {
+		t.Fatalf("messages %v after Dismiss", got)
+	}
+	c.Dismiss(1)
+	c.Dismiss("1")
+	if got := toastTexts(c); len(got) != 1 || got[0] != "success:three" {
+		t.Fatalf("messages %v after Dismiss", got)
+	}
+}
+
+func TestToastExpiry(t *testing.T) {
+	c := &Toast{Duration: 20 * time.Millisecond}
+	view.NewDriver("toast_expiry", c)
+	c.Info("gone")
+	if len(c.Messages()) != 1 {
+		t.Fatal("the message was not shown")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(c.Messages()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("messages %v after Duration", toastTexts(c))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// a message dismissed before it expires is removed once
+	c.Duration = time.Hour
+	c.Info("kept")
+	c.Dismiss(c.Messages()[0].ID)
+	c.remove(2)
+	if len(c.Messages()) != 0 {
+		t.Fatalf("messages %v", toastTexts(c))
+	}
+}
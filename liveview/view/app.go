@@ -0,0 +1,174 @@
+package view
+
+import (
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/arturoeanton/go-fiber-live-view/liveview/assets"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// App is the runtime shared by the pages of an application: it serves the assets of the
+// client once per router, it has the registry of pages used by the live navigation, the
+// Hub of the layouts alive, the sessions, and the template functions, shared templates
+// and components of mountEach registered in it. Two apps share none of them
+type App struct {
+	Router fiber.Router
+	Hub    *Hub
+	// Assets has json.wasm, wasm_exec.js and liveview.js, the embedded client by default
+	Assets fs.FS
+	// Lang and MaxFPS are the defaults of the pages that do not set them
+	Lang   string
+	MaxFPS int
+	// Secret sign the CSRF tokens of the pages, random when it is not set. The apps that run
+	// in several processes behind a load balancer must share it
+	Secret []byte
+	// Rejections count the websocket handshakes rejected by reason
+	Rejections Rejections
+	// Violations count the limits of the pages exceeded by the clients by kind
+	Violations Rejections
+
+	secretOnce   sync.Once
+	mu           sync.RWMutex
+	pages        map[string]*PageControl
+	assetRouters map[fiber.Router]bool
+	funcs        template.FuncMap
+	partials     *template.Template
+	components   map[string]func(id string) LiveDriver
+
+	muSessions sync.RWMutex
+	sessions   map[*websocket.Conn]*Session
+}
+
+// DefaultApp is the app of the pages registered without one and of the package functions
+// RegisterFunc, DefineTemplate and RegisterComponent, its hub is Layaouts
+var DefaultApp = newApp(nil, &Hub{mu: &MuLayout, layouts: Layaouts})
+
+var (
+	muApps sync.RWMutex
+	apps   = []*App{DefaultApp}
+)
+
+// NewApp create an app that register its pages in router
+func NewApp(router fiber.Router) *App {
+	a := newApp(router, NewHub())
+	muApps.Lock()
+	apps = append(apps, a)
+	muApps.Unlock()
+	return a
+}
+
+func newApp(router fiber.Router, hub *Hub) *App {
+	return &App{
+		Router:       router,
+		Hub:          hub,
+		Assets:       assets.FS,
+		pages:        make(map[string]*PageControl),
+		assetRouters: make(map[fiber.Router]bool),
+		funcs:        template.FuncMap{},
+		partials:     template.New("partials"),
+		components:   make(map[string]func(id string) LiveDriver),
+		sessions:     make(map[*websocket.Conn]*Session),
+	}
+}
+
+// allApps return a copy of the apps created
+func allApps() []*App {
+	muApps.RLock()
+	defer muApps.RUnlock()
+	return append([]*App(nil), apps...)
+}
+
+// currentApp return the app of the page whose function is running, DefaultApp outside
+// of them
+func currentApp() *App {
+	muBuilding.Lock()
+	defer muBuilding.Unlock()
+	if buildingApp != nil {
+		return buildingApp
+	}
+	return DefaultApp
+}
+
+// appOf return the app of page, the current app when page is nil
+func appOf(page *PageControl) *App {
+	if page != nil && page.App != nil {
+		return page.App
+	}
+	return currentApp()
+}
+
+// Page register pc in the app, pc.Router is the router of the app when it is not set
+func (a *App) Page(pc *PageControl, fx func() LiveDriver) *PageControl {
+	pc.App = a
+	if pc.Router == nil {
+		pc.Router = a.Router
+	}
+	pc.Register(fx)
+	return pc
+}
+
+// SendToAll deliver msg to every layout alive in the app
+func (a *App) SendToAll(msg interface{}) {
+	a.Hub.SendToAll(msg)
+}
+
+// SendTo deliver msg to the layouts with uuids
+func (a *App) SendTo(msg interface{}, uuids ...string) {
+	a.Hub.SendTo(msg, uuids...)
+}
+
+func (a *App) addPage(pc *PageControl) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pages[pc.Path] = pc
+}
+
+// pageFuncs return the functions of the pages of the app
+func (a *App) pageFuncs() template.FuncMap {
+	funcs := template.FuncMap{}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, pc := range a.pages {
+		for name, fn := range pc.Funcs {
+			funcs[name] = fn
+		}
+	}
+	return funcs
+}
+
+// PageFor return the page registered with path
+func (a *App) PageFor(path string) *PageControl {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.pages[path]
+}
+
+// registerAssets add the route /assets/:file to router, only the first time
+func (a *App) registerAssets(router fiber.Router) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.assetRouters[router] {
+		return
+	}
+	a.assetRouters[router] = true
+
+	router.Get("/assets/:file", func(c *fiber.Ctx) error {
+		file := path.Base(c.Params("file"))
+		content, err := fs.ReadFile(a.Assets, file)
+		if err != nil {
+			return c.SendStatus(http.StatusNotFound)
+		}
+		if file == "json.wasm" {
+			c.Set("Content-Type", "application/wasm")
+		}
+		if path.Ext(file) == ".js" {
+			c.Set("Content-Type", "application/javascript")
+		}
+		return c.Send(content)
+	})
+}
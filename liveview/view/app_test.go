@@ -0,0 +1,128 @@
+package view
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type greeting struct {
+	*ComponentDriver[*greeting]
+}
+
+func (c *greeting) GetDriver() LiveDriver { return c }
+func (c *greeting) Start()                {}
+func (c *greeting) GetTemplate() string {
+	return `<p>{{greet}} {{template "sign" .}}</p>`
+}
+
+func renderIn(t *testing.T, app *App) string {
+	t.Helper()
+	c := &greeting{}
+	tpl, err := componentTemplate(c, c.GetTemplate(), &PageControl{App: app})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func cached(app *App) int {
+	muTemplates.Lock()
+	defer muTemplates.Unlock()
+	n := 0
+	for key := range templates {
+		if key.app == app && key.typ == reflect.TypeOf(&greeting{}) {
+			n++
+		}
+	}
+	return n
+}
+
+// TestAppIsolation check that the functions, shared templates and components of an app
+// are not seen by the other ones, and that changing an app keeps the cache of the others
+func TestAppIsolation(t *testing.T) {
+	a, b := NewApp(nil), NewApp(nil)
+	a.RegisterFunc("greet", func() string { return "hola" })
+	b.RegisterFunc("greet", func() string { return "hello" })
+	if err := a.DefineTemplate("sign", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.DefineTemplate("sign", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := renderIn(t, a); got != "<p>hola a</p>" {
+		t.Errorf("app a rendered %q", got)
+	}
+	if got := renderIn(t, b); got != "<p>hello b</p>" {
+		t.Errorf("app b rendered %q", got)
+	}
+	if cached(a) != 1 || cached(b) != 1 {
+		t.Fatalf("cached templates a=%d b=%d, want 1 and 1", cached(a), cached(b))
+	}
+
+	a.RegisterFunc("greet", func() string { return "buenas" })
+	if cached(a) != 0 || cached(b) != 1 {
+		t.Errorf("after RegisterFunc in a cached templates a=%d b=%d, want 0 and 1", cached(a), cached(b))
+	}
+	if got := renderIn(t, a); got != "<p>buenas a</p>" {
+		t.Errorf("app a rendered %q after RegisterFunc", got)
+	}
+	if err := b.DefineTemplate("sign", "b2"); err != nil {
+		t.Fatal(err)
+	}
+	if cached(a) != 1 || cached(b) != 0 {
+		t.Errorf("after DefineTemplate in b cached templates a=%d b=%d, want 1 and 0", cached(a), cached(b))
+	}
+	if got := renderIn(t, b); got != "<p>hello b2</p>" {
+		t.Errorf("app b rendered %q after DefineTemplate", got)
+	}
+
+	RegisterComponentIn(a, "greeting", func() *greeting { return &greeting{} })
+	if _, ok := a.component("greeting"); !ok {
+		t.Error("component not registered in a")
+	}
+	if _, ok := b.component("greeting"); ok {
+		t.Error("component of a registered in b")
+	}
+}
+
+// TestHubBroadcast check that SendToAll deliver to every layout of the hub and SendTo
+// only to the ones with the uuids
+func TestHubBroadcast(t *testing.T) {
+	h := NewHub()
+	got := map[string][]interface{}{}
+	for _, uid := range []string{"l1", "l2", "l3"} {
+		uid := uid
+		h.Add(&Layout{UUID: uid, HandlerEventIn: func(data interface{}) {
+			got[uid] = append(got[uid], data)
+		}})
+	}
+	other := NewHub()
+	other.Add(&Layout{UUID: "l1", HandlerEventIn: func(data interface{}) {
+		t.Errorf("layout of another hub received %v", data)
+	}})
+
+	h.SendToAll("all")
+	h.SendTo("some", "l1", "l3", "missing")
+	want := map[string][]interface{}{
+		"l1": {"all", "some"},
+		"l2": {"all"},
+		"l3": {"all", "some"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+
+	h.Delete("l2")
+	if h.Len() != 2 {
+		t.Errorf("Len() = %d after Delete, want 2", h.Len())
+	}
+	if _, ok := h.Get("l2"); ok {
+		t.Error("l2 still in the hub")
+	}
+}
@@ -0,0 +1,189 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ModelChanger is implemented by the components that want to know when the browser changed
+// one of their fields bound with lv-model. The changes of a session are delivered in order
+// by a goroutine of the session, out of its read loop, so ModelChanged can read the DOM
+// with GetValue or GetElementById. The field is set and ModelChanged invoked holding the
+// lock of the state of the component, like the event handlers (see Do)
+type ModelChanger interface {
+	ModelChanged(field string)
+}
+
+var modelAttr = regexp.MustCompile(`lv-model="([^"]+)"`)
+
+// bindModels remember the fields bound with lv-model in the html of the component, the
+// browser can only set those fields
+func (cw *ComponentDriver[T]) bindModels(html []byte) {
+	var models map[string]bool
+	if bytes.Contains(html, []byte("lv-model")) {
+		models = make(map[string]bool)
+		for _, m := range modelAttr.FindAllSubmatch(html, -1) {
+			models[string(m[1])] = true
+		}
+	}
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	cw.models = models
+}
+
+// SetModel set the field of the component bound with lv-model="path" to value, converted
+// to the type of the field. The path can name nested fields, like "Address.City". It is
+// called from the handlers, the goroutines of Go use it inside Do
+func (cw *ComponentDriver[T]) SetModel(path string, value string) error {
+	if err := cw.setModel(path, value); err != nil {
+		return err
+	}
+	if m, ok := any(cw.Component).(ModelChanger); ok {
+		func() {
+			defer HandleRecover()
+			m.ModelChanged(path)
+		}()
+	}
+	return nil
+}
+
+// checkModel check that the browser can set the field path to value, without changing
+// the component
+func (cw *ComponentDriver[T]) checkModel(path string, value string) error {
+	cw.muTree.Lock()
+	bound := cw.models[path]
+	cw.muTree.Unlock()
+	if !bound {
+		return fmt.Errorf("field %q is not bound with lv-model", path)
+	}
+	t, err := fieldTypeByPath(reflect.TypeOf(cw.Component), path)
+	if err != nil {
+		return err
+	}
+	if err := SetFieldString(reflect.New(t).Elem(), value); err != nil {
+		return fmt.Errorf("field %q: %w", path, err)
+	}
+	return nil
+}
+
+// setModel is SetModel without ModelChanged
+func (cw *ComponentDriver[T]) setModel(path string, value string) error {
+	cw.muTree.Lock()
+	bound := cw.models[path]
+	cw.muTree.Unlock()
+	if !bound {
+		return fmt.Errorf("field %q is not bound with lv-model", path)
+	}
+	field, err := fieldByPath(reflect.ValueOf(cw.Component), path)
+	if err != nil {
+		return err
+	}
+	if err := SetFieldString(field, value); err != nil {
+		return fmt.Errorf("field %q: %w", path, err)
+	}
+	return nil
+}
+
+// fieldByPath return the field of v named by the dotted path, the nil pointers on the
+// way are allocated
+func fieldByPath(v reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("field %q: nil pointer", path)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("field %q: %s is not a struct", path, v.Type())
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("field %q not found", path)
+		}
+	}
+	return v, nil
+}
+
+// fieldTypeByPath return the type of the field of t named by the dotted path
+func fieldTypeByPath(t reflect.Type, path string) (reflect.Type, error) {
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("field %q: %s is not a struct", path, t)
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", path)
+		}
+		if !f.IsExported() {
+			return nil, fmt.Errorf("field %q is not exported", path)
+		}
+		t = f.Type
+	}
+	return t, nil
+}
+
+// maxModelChanges is the number of lv-model changes waiting in a session, the next
+// changes are refused until the queue has room
+const maxModelChanges = 256
+
+type modelSetter interface {
+	checkModel(path string, value string) error
+	setModel(path string, value string) error
+	Do(fx func())
+}
+
+// setModel apply the message "model" of the browser, id is the component owning the input
+// or "content" for the page. The value is checked in the read loop, the field is set and
+// ModelChanged invoked in the queue of the session holding the lock of the component, so
+// the read loop does not wait for the handlers
+func (s *Session) setModel(id string, path string, value string) error {
+	d := s.driver(id)
+	setter, ok := d.(modelSetter)
+	if !ok {
+		return protocolError(ErrCodeUnknownComponent, "%q", id)
+	}
+	// the read loop is the only sender, the room checked is still there when it sends
+	if s.modelChanges != nil && len(s.modelChanges) == cap(s.modelChanges) {
+		return protocolError(ErrCodeInvalidModel, "%s: too many changes pending", id)
+	}
+	if err := setter.checkModel(path, value); err != nil {
+		return protocolError(ErrCodeInvalidModel, "%s: %v", id, err)
+	}
+	m, changer := d.GetComponet().(ModelChanger)
+	changed := func() {
+		defer HandleRecover()
+		setter.Do(func() {
+			if err := setter.setModel(path, value); err != nil {
+				log.Println("lv-model", id, err)
+				return
+			}
+			if changer {
+				m.ModelChanged(path)
+			}
+		})
+	}
+	if s.modelChanges == nil {
+		changed()
+		return nil
+	}
+	s.modelChanges <- changed
+	return nil
+}
+
+// runModelChanges invoke the lv-model changes queued until the session is closed
+func (s *Session) runModelChanges() {
+	for changed := range s.modelChanges {
+		changed()
+	}
+}
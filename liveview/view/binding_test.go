@@ -0,0 +1,120 @@
+package view
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type modelProbe struct {
+	*ComponentDriver[*modelProbe]
+	Name string
+	got  chan string
+}
+
+func (c *modelProbe) GetDriver() LiveDriver { return c }
+func (c *modelProbe) Start()                { c.Commit() }
+func (c *modelProbe) GetTemplate() string {
+	return `<div id="{{.IdComponent}}"><input lv-model="Name"></div>`
+}
+
+// ModelChanged read the DOM, the reply arrives by the read loop of the session
+func (c *modelProbe) ModelChanged(field string) {
+	c.got <- c.Name + ":" + c.GetElementById("other")
+}
+
+func TestModelChangedReadsDOM(t *testing.T) {
+	got := make(chan string, 1)
+	_, conn := dialPage(t, &PageControl{}, func() LiveDriver {
+		New("probe", &modelProbe{got: got})
+		return NewLayout("model_"+uuid.NewString(), `<div>{{mount "probe"}}</div>`)
+	})
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	// the model is bound once the component is rendered
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(msg), "lv-model") {
+			break
+		}
+	}
+	if err := conn.WriteJSON(map[string]interface{}{"v": 1, "type": "model", "id": "probe", "field": "Name", "data": "ana"}); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg["type"] == "get" {
+			reply := map[string]interface{}{"v": 1, "type": "get", "id_ret": msg["id_ret"], "data": "dom"}
+			if err := conn.WriteJSON(reply); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	select {
+	case v := <-got:
+		if v != "ana:dom" {
+			t.Fatalf("ModelChanged got %q, want %q", v, "ana:dom")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ModelChanged did not receive the reply of the browser")
+	}
+}
+
+type modelKeys struct {
+	*ComponentDriver[*modelKeys]
+	Name string
+	keys chan string
+}
+
+func (c *modelKeys) GetDriver() LiveDriver { return c }
+func (c *modelKeys) Start()                { c.Commit() }
+func (c *modelKeys) GetTemplate() string {
+	return `<div id="{{.IdComponent}}"><input lv-model="Name" lv-keyup="KeyUp"></div>`
+}
+
+// KeyUp read the field written by the lv-model messages
+func (c *modelKeys) KeyUp(data interface{}) {
+	c.keys <- c.Name
+}
+
+func TestModelWithHandlers(t *testing.T) {
+	keys := make(chan string, 64)
+	_, conn := dialPage(t, &PageControl{}, func() LiveDriver {
+		return NewDriver("keys", &modelKeys{keys: keys})
+	})
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(msg), "lv-model") {
+			break
+		}
+	}
+	for i := 0; i < 20; i++ {
+		model := map[string]interface{}{"v": 1, "type": "model", "id": "keys", "field": "Name", "data": strings.Repeat("a", i)}
+		event := map[string]interface{}{"v": 1, "type": "data", "id": "keys", "event": "KeyUp"}
+		if err := conn.WriteJSON(model); err != nil {
+			t.Fatal(err)
+		}
+		if err := conn.WriteJSON(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		select {
+		case <-keys:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%d handlers run, want 20", i)
+		}
+	}
+}
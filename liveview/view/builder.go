@@ -0,0 +1,247 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Renderer is implemented by components that write their html in Go instead of using
+// GetTemplate, Commit prefers it over the template
+type Renderer interface {
+	Render(w io.Writer) error
+}
+
+// ContextRenderer is the same as Renderer with the context of the component, it is the
+// signature of the components generated by templ
+type ContextRenderer interface {
+	Render(ctx context.Context, w io.Writer) error
+}
+
+// Node is a piece of html built with El, Text, Raw...
+type Node interface {
+	Render(w io.Writer) error
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// nameRegexp match the names of the tags and the attributes of the builder
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-:]*$`)
+
+// urlAttributes are the attributes whose value is a url, their value is sanitized
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "xlink:href": true,
+	"poster": true, "cite": true, "background": true, "manifest": true, "data": true,
+}
+
+// checkAttrName refuse the names that are not attributes, the event handlers (on*) and
+// srcdoc, whose value is html
+func checkAttrName(name string) error {
+	lower := strings.ToLower(name)
+	if !nameRegexp.MatchString(name) || strings.HasPrefix(lower, "on") || lower == "srcdoc" {
+		return fmt.Errorf("%q is not a safe attribute", name)
+	}
+	return nil
+}
+
+// safeURL return u when it is relative or its scheme is http, https, mailto or tel, and
+// "#ZgotmplZ", like html/template, for the rest (javascript:, data:...)
+func safeURL(u string) string {
+	trimmed := strings.TrimSpace(u)
+	if i := strings.IndexAny(trimmed, ":/?#"); i >= 0 && trimmed[i] == ':' {
+		switch strings.ToLower(trimmed[:i]) {
+		case "http", "https", "mailto", "tel":
+		default:
+			return "#ZgotmplZ"
+		}
+	}
+	return u
+}
+
+type attribute struct {
+	name  string
+	value string
+	bare  bool
+	err   error
+}
+
+func (a attribute) Render(w io.Writer) error {
+	if a.err != nil {
+		return a.err
+	}
+	if a.bare {
+		_, err := io.WriteString(w, " "+a.name)
+		return err
+	}
+	_, err := io.WriteString(w, " "+a.name+`="`+html.EscapeString(a.value)+`"`)
+	return err
+}
+
+// Attr is the attribute name="value" of the element where it is a child, value is escaped
+// and the urls of href, src, action... are sanitized. The event handlers (on*) and the
+// names not valid are an error when the element is rendered
+func Attr(name, value string) Node {
+	if err := checkAttrName(name); err != nil {
+		return attribute{err: fmt.Errorf("Attr: %w", err)}
+	}
+	if urlAttributes[strings.ToLower(name)] {
+		value = safeURL(value)
+	}
+	return attribute{name: name, value: value}
+}
+
+// BoolAttr is an attribute without value, like disabled or checked, when enabled is true
+func BoolAttr(name string, enabled bool) Node {
+	if !enabled {
+		return Group()
+	}
+	if err := checkAttrName(name); err != nil {
+		return attribute{err: fmt.Errorf("BoolAttr: %w", err)}
+	}
+	return attribute{name: name, bare: true}
+}
+
+// ID is the attribute id
+func ID(id string) Node {
+	return Attr("id", id)
+}
+
+// Class is the attribute class with the classes joined
+func Class(classes ...string) Node {
+	return Attr("class", classList(stringsToArgs(classes)...))
+}
+
+func stringsToArgs(s []string) []interface{} {
+	args := make([]interface{}, 0, len(s)*2)
+	for _, v := range s {
+		args = append(args, v, true)
+	}
+	return args
+}
+
+type element struct {
+	tag      string
+	children []Node
+}
+
+// El is the element tag, the Attr in children are its attributes and the rest its content.
+// A tag not valid is an error when it is rendered
+func El(tag string, children ...Node) Node {
+	return element{tag: tag, children: children}
+}
+
+func (e element) Render(w io.Writer) error {
+	if !nameRegexp.MatchString(e.tag) {
+		return fmt.Errorf("El: %q is not a valid tag", e.tag)
+	}
+	if _, err := io.WriteString(w, "<"+e.tag); err != nil {
+		return err
+	}
+	for _, c := range flatten(e.children) {
+		if a, ok := c.(attribute); ok {
+			if err := a.Render(w); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := io.WriteString(w, ">"); err != nil {
+		return err
+	}
+	if voidElements[e.tag] {
+		return nil
+	}
+	for _, c := range flatten(e.children) {
+		if _, ok := c.(attribute); ok {
+			continue
+		}
+		if err := c.Render(w); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</"+e.tag+">")
+	return err
+}
+
+type group []Node
+
+// Group render the nodes one after the other, the attributes of a group belong to the
+// element containing it
+func Group(nodes ...Node) Node {
+	return group(nodes)
+}
+
+func (g group) Render(w io.Writer) error {
+	for _, n := range g {
+		if err := n.Render(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten expand the groups, so their attributes reach the element
+func flatten(nodes []Node) []Node {
+	out := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		if g, ok := n.(group); ok {
+			out = append(out, flatten(g)...)
+			continue
+		}
+		if n != nil {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+type text string
+
+func (t text) Render(w io.Writer) error {
+	_, err := io.WriteString(w, html.EscapeString(string(t)))
+	return err
+}
+
+// Text is escaped text
+func Text(s string) Node {
+	return text(s)
+}
+
+type raw string
+
+func (r raw) Render(w io.Writer) error {
+	_, err := io.WriteString(w, string(r))
+	return err
+}
+
+// Raw is trusted html written as it is
+func Raw(s SafeHTML) Node {
+	return raw(s)
+}
+
+// If return n when cond is true
+func If(cond bool, n Node) Node {
+	if !cond {
+		return Group()
+	}
+	return n
+}
+
+// Each build one node for each item
+func Each[T any](items []T, fx func(item T) Node) Node {
+	nodes := make([]Node, 0, len(items))
+	for _, item := range items {
+		nodes = append(nodes, fx(item))
+	}
+	return Group(nodes...)
+}
+
+// Mount is the mount point of the component id, the same as {{mount "id"}}
+func Mount(id string) Node {
+	return El("span", ID("mount_span_"+id))
+}
@@ -0,0 +1,100 @@
+package view
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// counter is rendered from Go with the builder instead of a template
+type counter struct {
+	*ComponentDriver[*counter]
+	Count int
+	Tags  []string
+}
+
+func (c *counter) GetDriver() LiveDriver { return c }
+func (c *counter) Start()                {}
+func (c *counter) GetTemplate() string   { return "" }
+
+func (c *counter) Render(w io.Writer) error {
+	return El("div", ID(c.IdComponent),
+		El("span", Class("count", "even"), Text(strconv.Itoa(c.Count))),
+		If(c.Count == 0, El("em", Text("none"))),
+		El("ul", Each(c.Tags, func(tag string) Node {
+			return El("li", Attr("data-tag", tag), Text(tag))
+		})),
+		Mount("child"),
+	).Render(w)
+}
+
+// TestRenderer check that the driver render with Render the components that implement it
+func TestRenderer(t *testing.T) {
+	c := &counter{Count: 2, Tags: []string{"a", "<b>"}}
+	d := NewDriver("counter", c)
+	var b strings.Builder
+	if err := d.render(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `<div id="counter"><span class="count even">2</span><ul><li data-tag="a">a</li>` +
+		`<li data-tag="&lt;b&gt;">&lt;b&gt;</li></ul><span id="mount_span_child"></span></div>`
+	if b.String() != want {
+		t.Fatalf("render = %s, want %s", b.String(), want)
+	}
+	if err := CheckTemplate(c); err != nil {
+		t.Errorf("CheckTemplate of a Renderer: %v", err)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		node Node
+		want string
+		err  string
+	}{
+		{"escaped", El("p", Class("a", "b"), Text("<b>")), `<p class="a b">&lt;b&gt;</p>`, ""},
+		{"void", El("input", Attr("lv-model", "Name"), BoolAttr("disabled", true)), `<input lv-model="Name" disabled>`, ""},
+		{"value", El("a", Attr("title", `"x"`)), `<a title="&#34;x&#34;"></a>`, ""},
+		{"relative url", El("a", Attr("href", "/next?a=1")), `<a href="/next?a=1"></a>`, ""},
+		{"http url", El("a", Attr("href", "https://example.com")), `<a href="https://example.com"></a>`, ""},
+		{"javascript url", El("a", Attr("href", "javascript:alert(1)")), `<a href="#ZgotmplZ"></a>`, ""},
+		{"hidden scheme", El("img", Attr("SRC", " JaVa\tScRiPt:alert(1)")), `<img SRC="#ZgotmplZ">`, ""},
+		{"data url", El("a", Attr("href", "data:text/html,x")), `<a href="#ZgotmplZ"></a>`, ""},
+		{"event handler", El("a", Attr("onclick", "x()")), "", "not a safe attribute"},
+		{"bool event handler", El("a", BoolAttr("ONload", true)), "", "not a safe attribute"},
+		{"srcdoc", El("iframe", Attr("srcdoc", "<p>")), "", "not a safe attribute"},
+		{"attribute injection", El("a", Attr(`x="1" onclick="x()"`, "")), "", "not a safe attribute"},
+		{"tag injection", El(`a onclick="x()"`), "", "not a valid tag"},
+		{"empty tag", El(""), "", "not a valid tag"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			err := tc.node.Render(&b)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("Render error %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tc.want {
+				t.Fatalf("Render = %s, want %s", b.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestAttrFunc(t *testing.T) {
+	for _, name := range []string{"onclick", "href", "style", "srcdoc", `a"b`, "x y"} {
+		if _, err := attr(name, "v"); err == nil {
+			t.Errorf("attr(%q) is allowed", name)
+		}
+	}
+	if a, err := attr("data-id", `<1>`); err != nil || a != `data-id="&lt;1&gt;"` {
+		t.Errorf("attr(data-id) = %q, %v", a, err)
+	}
+}
@@ -0,0 +1,80 @@
+package view
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type convertTarget struct {
+	S   string
+	B   bool
+	I   int8
+	U   uint
+	F   float32
+	D   time.Duration
+	T   time.Time
+	P   *int
+	Bad chan int
+}
+
+func TestSetFieldString(t *testing.T) {
+	for _, tc := range []struct {
+		field string
+		value string
+		want  interface{}
+		err   bool
+	}{
+		{"S", "text", "text", false},
+		{"B", "true", true, false},
+		{"B", "on", true, false},
+		{"B", "", false, false},
+		{"B", "yes", nil, true},
+		{"I", "-12", int8(-12), false},
+		{"I", "", int8(0), false},
+		{"I", "300", nil, true},
+		{"I", "1.5", nil, true},
+		{"U", "7", uint(7), false},
+		{"U", "-1", nil, true},
+		{"F", "1.5", float32(1.5), false},
+		{"F", "x", nil, true},
+		{"D", "1m30s", 90 * time.Second, false},
+		{"D", "90", nil, true},
+		{"T", "2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"T", "2024-01-02T10:30", time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"T", "", time.Time{}, false},
+		{"T", "yesterday", nil, true},
+		{"Bad", "1", nil, true},
+	} {
+		var target convertTarget
+		field := reflect.ValueOf(&target).Elem().FieldByName(tc.field)
+		err := SetFieldString(field, tc.value)
+		if (err != nil) != tc.err {
+			t.Errorf("SetFieldString(%s, %q) error %v", tc.field, tc.value, err)
+			continue
+		}
+		if !tc.err && !reflect.DeepEqual(field.Interface(), tc.want) {
+			t.Errorf("SetFieldString(%s, %q) = %v, want %v", tc.field, tc.value, field.Interface(), tc.want)
+		}
+	}
+}
+
+func TestSetFieldStringPointer(t *testing.T) {
+	var target convertTarget
+	field := reflect.ValueOf(&target).Elem().FieldByName("P")
+	if err := SetFieldString(field, "5"); err != nil || target.P == nil || *target.P != 5 {
+		t.Fatalf("pointer not set: %v", err)
+	}
+	if FieldString(field) != "5" {
+		t.Fatalf("FieldString = %q", FieldString(field))
+	}
+	if err := SetFieldString(field, ""); err != nil || target.P != nil {
+		t.Fatalf("empty value did not clear the pointer: %v", err)
+	}
+	if err := SetFieldString(field, "x"); err == nil {
+		t.Fatal("invalid value accepted")
+	}
+	if err := SetFieldString(reflect.ValueOf(target).FieldByName("S"), "x"); err == nil {
+		t.Fatal("a field that can not be set was set")
+	}
+}
@@ -0,0 +1,81 @@
+package view
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		err  bool
+	}{
+		{"* * * * *", false},
+		{"*/15 9-18 * * 1-5", false},
+		{"0,30 0-23/2 1,15 */3 *", false},
+		{"0 0 * jan-mar MON-fri", false},
+		{"0 0 * * 7", false},
+		{"@daily", false},
+		{" @hourly ", false},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"*/x * * * *", true},
+		{"a * * * *", true},
+		{"1- * * * *", true},
+		{"* * * foo *", true},
+		{"* * * * mon-", true},
+		{"@weekdays", true},
+	} {
+		_, err := ParseCron(tc.spec)
+		if (err != nil) != tc.err {
+			t.Errorf("ParseCron(%q) error %v, want error %v", tc.spec, err, tc.err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-01-01 is a monday
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for _, tc := range []struct {
+		spec, from, want string
+	}{
+		{"* * * * *", "2024-01-01 10:00", "2024-01-01 10:01"},
+		{"*/15 * * * *", "2024-01-01 10:01", "2024-01-01 10:15"},
+		{"10-20/5 * * * *", "2024-01-01 10:16", "2024-01-01 10:20"},
+		{"0,30 * * * *", "2024-01-01 10:10", "2024-01-01 10:30"},
+		{"0 9-18 * * 1-5", "2024-01-05 19:00", "2024-01-08 09:00"},
+		{"0 0 * * sun", "2024-01-01 00:00", "2024-01-07 00:00"},
+		{"0 0 * * 7", "2024-01-01 00:00", "2024-01-07 00:00"},
+		{"0 0 1 feb *", "2024-01-01 00:00", "2024-02-01 00:00"},
+		{"0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		{"@monthly", "2024-01-15 00:00", "2024-02-01 00:00"},
+		// day of month and day of week restricted: either of them
+		{"0 0 15 * mon", "2024-01-02 00:00", "2024-01-08 00:00"},
+		{"0 0 3 * mon", "2024-01-02 00:00", "2024-01-03 00:00"},
+		// a step from * does not restrict the day: both of them
+		{"0 0 */2 * mon", "2024-01-02 00:00", "2024-01-15 00:00"},
+		{"0 0 2 * */2", "2024-01-01 00:00", "2024-01-02 00:00"},
+		{"0 0 1 * */3", "2024-01-01 00:00", "2024-05-01 00:00"},
+	} {
+		s, err := ParseCron(tc.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Next(at(tc.from)); !got.Equal(at(tc.want)) {
+			t.Errorf("%q after %s = %s, want %s", tc.spec, tc.from, got.Format("2006-01-02 15:04"), tc.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package view
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CSPNonce is replaced by 'nonce-<nonce of the request>' in the sources of a CSP
+const CSPNonce = "'nonce'"
+
+// CSP build the header Content-Security-Policy of a page. The shell of the page has no
+// inline scripts, its script and style tags have the nonce of the request
+type CSP struct {
+	// ReportOnly send the header Content-Security-Policy-Report-Only instead
+	ReportOnly bool
+
+	mu         sync.RWMutex
+	directives map[string][]string
+}
+
+// NewCSP return a strict policy that works with the live views: the scripts of the page
+// origin and with the nonce, 'wasm-unsafe-eval' to start the client and style attributes,
+// used by the components
+func NewCSP() *CSP {
+	return &CSP{directives: map[string][]string{
+		"default-src":     {"'self'"},
+		"script-src":      {"'self'", CSPNonce, "'wasm-unsafe-eval'"},
+		"style-src":       {"'self'", CSPNonce},
+		"style-src-attr":  {"'unsafe-inline'"},
+		"img-src":         {"'self'", "data:"},
+		"connect-src":     {"'self'"},
+		"object-src":      {"'none'"},
+		"base-uri":        {"'self'"},
+		"frame-ancestors": {"'self'"},
+	}}
+}
+
+// Add append sources to directive
+func (c *CSP) Add(directive string, sources ...string) *CSP {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.directives == nil {
+		c.directives = make(map[string][]string)
+	}
+	c.directives[directive] = append(c.directives[directive], sources...)
+	return c
+}
+
+// Set replace the sources of directive, without sources the directive is removed
+func (c *CSP) Set(directive string, sources ...string) *CSP {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.directives == nil {
+		c.directives = make(map[string][]string)
+	}
+	if len(sources) == 0 {
+		delete(c.directives, directive)
+		return c
+	}
+	c.directives[directive] = sources
+	return c
+}
+
+// Header return the name of the header
+func (c *CSP) Header() string {
+	if c.ReportOnly {
+		return "Content-Security-Policy-Report-Only"
+	}
+	return "Content-Security-Policy"
+}
+
+// String return the policy with nonce
+func (c *CSP) String(nonce string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.directives))
+	for name := range c.directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		sources := make([]string, 0, len(c.directives[name]))
+		for _, source := range c.directives[name] {
+			if source == CSPNonce {
+				source = "'nonce-" + nonce + "'"
+			}
+			sources = append(sources, source)
+		}
+		parts = append(parts, strings.TrimSpace(name+" "+strings.Join(sources, " ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// newNonce return a random nonce for the scripts of a request
+func newNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
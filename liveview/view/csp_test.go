@@ -0,0 +1,77 @@
+package view
+
+import (
+	"html"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCSPString(t *testing.T) {
+	csp := (&CSP{}).Add("script-src", "'self'", CSPNonce).Add("img-src", "data:").Set("img-src")
+	if got, want := csp.String("abc"), "script-src 'self' 'nonce-abc'"; got != want {
+		t.Fatalf("String = %q, want %q", got, want)
+	}
+	if csp.Header() != "Content-Security-Policy" {
+		t.Fatal(csp.Header())
+	}
+	csp.ReportOnly = true
+	if csp.Header() != "Content-Security-Policy-Report-Only" {
+		t.Fatal(csp.Header())
+	}
+}
+
+var (
+	cspNonceRegex = regexp.MustCompile(`'nonce-([^']+)'`)
+	tagRegex      = regexp.MustCompile(`<(script|style)[^>]*>`)
+	nonceRegex    = regexp.MustCompile(`nonce="([^"]*)"`)
+)
+
+func TestShellNonce(t *testing.T) {
+	router := fiber.New(fiber.Config{DisableStartupMessage: true})
+	NewApp(router).Page(&PageControl{
+		Path:      "/",
+		CSP:       NewCSP(),
+		HeadCode:  `<script nonce="{{.Nonce}}" src="head.js"></script>`,
+		AfterCode: `<script nonce="{{.Nonce}}">console.log("after")</script>`,
+	}, func() LiveDriver { return NewLayout("csp_test", `<div></div>`) })
+
+	nonces := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		resp, err := router.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		header := resp.Header.Get("Content-Security-Policy")
+		m := cspNonceRegex.FindStringSubmatch(header)
+		if m == nil {
+			t.Fatalf("the CSP has no nonce: %q", header)
+		}
+		nonce := m[1]
+		nonces[nonce] = true
+
+		tags := tagRegex.FindAllString(string(body), -1)
+		if len(tags) != 5 {
+			t.Fatalf("%d script and style tags, want 5: %q", len(tags), tags)
+		}
+		for _, tag := range tags {
+			m := nonceRegex.FindStringSubmatch(tag)
+			if m == nil || html.UnescapeString(m[1]) != nonce {
+				t.Fatalf("tag %s has not the nonce %s of the CSP", tag, nonce)
+			}
+		}
+		for _, code := range []string{`src="head.js"`, `console.log("after")`} {
+			if !strings.Contains(string(body), code) {
+				t.Fatalf("the page has not %s:\n%s", code, body)
+			}
+		}
+	}
+	if len(nonces) != 2 {
+		t.Fatal("two requests got the same nonce")
+	}
+}
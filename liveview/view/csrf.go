@@ -0,0 +1,200 @@
+package view
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultCSRFMaxAge is the age limit of the CSRF tokens when PageControl.CSRFMaxAge is 0
+const DefaultCSRFMaxAge = 12 * time.Hour
+
+const (
+	// csrfCookie identify the browser, the tokens are only valid with its cookie
+	csrfCookie = "lv_csrf"
+	// csrfParam is the parameter of the websocket url with the token
+	csrfParam = "lv_csrf"
+)
+
+// the reasons of the websocket connections rejected
+const (
+	RejectOrigin = "origin"
+	RejectCSRF   = "csrf"
+)
+
+// Rejections count the websocket connections rejected by reason
+type Rejections struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// Add count a rejection
+func (r *Rejections) Add(reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil {
+		r.counts = make(map[string]int64)
+	}
+	r.counts[reason]++
+}
+
+// Count return the rejections of reason
+func (r *Rejections) Count(reason string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[reason]
+}
+
+// Counts return a copy of the rejections by reason
+func (r *Rejections) Counts() map[string]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int64, len(r.counts))
+	for reason, n := range r.counts {
+		counts[reason] = n
+	}
+	return counts
+}
+
+// secret return the key of the CSRF tokens of the app, a random one when Secret is not
+// set. Set Secret when the app runs in more than one process
+func (a *App) secret() []byte {
+	a.secretOnce.Do(func() {
+		if len(a.Secret) > 0 {
+			return
+		}
+		a.Secret = make([]byte, 32)
+		if _, err := rand.Read(a.Secret); err != nil {
+			panic(err)
+		}
+	})
+	return a.Secret
+}
+
+// browserID return the cookie identifying the browser, it is created the first time
+func browserID(c *fiber.Ctx) string {
+	id := c.Cookies(csrfCookie)
+	if id != "" {
+		return id
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	id = base64.RawURLEncoding.EncodeToString(b)
+	c.Cookie(&fiber.Cookie{
+		Name:     csrfCookie,
+		Value:    id,
+		Path:     "/",
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return id
+}
+
+// csrfToken sign the page and the browser with the time the token is issued
+func (pc *PageControl) csrfToken(browser string, issued time.Time) string {
+	ts := strconv.FormatInt(issued.Unix(), 10)
+	return ts + "." + base64.RawURLEncoding.EncodeToString(pc.csrfMAC(browser, ts))
+}
+
+func (pc *PageControl) csrfMAC(browser string, ts string) []byte {
+	mac := hmac.New(sha256.New, pc.App.secret())
+	mac.Write([]byte(pc.Path + "\n" + browser + "\n" + ts))
+	return mac.Sum(nil)
+}
+
+// checkCSRF verify the token of the websocket url
+func (pc *PageControl) checkCSRF(browser string, token string, now time.Time) error {
+	ts, sig, ok := strings.Cut(token, ".")
+	if !ok || browser == "" {
+		return fmt.Errorf("missing csrf token")
+	}
+	issued, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid csrf token")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, pc.csrfMAC(browser, ts)) {
+		return fmt.Errorf("invalid csrf token")
+	}
+	maxAge := pc.CSRFMaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultCSRFMaxAge
+	}
+	if age := now.Sub(time.Unix(issued, 0)); age > maxAge || age < -time.Minute {
+		return fmt.Errorf("expired csrf token")
+	}
+	return nil
+}
+
+// checkOrigin verify the header Origin of the websocket handshake. Without AllowedOrigins
+// only the origin of the page is allowed, "*" allows any origin. The requests without
+// Origin are not from a browser and they are allowed
+func (pc *PageControl) checkOrigin(c *fiber.Ctx) error {
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return fmt.Errorf("invalid origin %q", origin)
+	}
+	if len(pc.AllowedOrigins) == 0 {
+		if strings.EqualFold(u.Host, c.Hostname()) {
+			return nil
+		}
+		return fmt.Errorf("origin %q not allowed", origin)
+	}
+	for _, allowed := range pc.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %q not allowed", origin)
+}
+
+// checkHandshake verify the origin and the csrf token of the websocket handshake, the
+// rejections are counted in the app and reported to OnReject
+func (pc *PageControl) checkHandshake(c *fiber.Ctx) bool {
+	reason := ""
+	err := pc.checkOrigin(c)
+	if err != nil {
+		reason = RejectOrigin
+	} else if !pc.DisableCSRF {
+		if err = pc.checkCSRF(c.Cookies(csrfCookie), c.Query(csrfParam), time.Now()); err != nil {
+			reason = RejectCSRF
+		}
+	}
+	if err == nil {
+		return true
+	}
+	pc.App.Rejections.Add(reason)
+	if pc.OnReject != nil {
+		pc.OnReject(c, reason, err)
+	}
+	return false
+}
+
+// withoutParam return the query without the parameter name
+func withoutParam(rawQuery string, name string) string {
+	parts := strings.Split(rawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		if part == "" || part == name || strings.HasPrefix(part, name+"=") {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return strings.Join(kept, "&")
+}
@@ -0,0 +1,65 @@
+package view
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSRF(t *testing.T) {
+	app := newApp(nil, NewHub())
+	app.Secret = []byte("secret")
+	home := &PageControl{Path: "/", App: app}
+	next := &PageControl{Path: "/next", App: app, CSRFMaxAge: time.Minute}
+	now := time.Unix(1700000000, 0)
+	token := home.csrfToken("browser", now)
+
+	for _, tc := range []struct {
+		name    string
+		page    *PageControl
+		browser string
+		token   string
+		now     time.Time
+		err     string
+	}{
+		{"valid", home, "browser", token, now, ""},
+		{"before max age", home, "browser", token, now.Add(DefaultCSRFMaxAge - time.Second), ""},
+		{"expired", home, "browser", token, now.Add(DefaultCSRFMaxAge + time.Second), "expired"},
+		{"page max age", next, "browser", next.csrfToken("browser", now), now.Add(2 * time.Minute), "expired"},
+		{"issued in the future", home, "browser", home.csrfToken("browser", now.Add(time.Hour)), now, "expired"},
+		{"other browser", home, "other", token, now, "invalid"},
+		{"other page", next, "browser", token, now, "invalid"},
+		{"other secret", &PageControl{Path: "/", App: &App{Secret: []byte("other")}}, "browser", token, now, "invalid"},
+		{"without browser", home, "", token, now, "missing"},
+		{"empty", home, "browser", "", now, "missing"},
+		{"bad time", home, "browser", "x." + strings.SplitN(token, ".", 2)[1], now, "invalid"},
+		{"bad signature", home, "browser", strings.SplitN(token, ".", 2)[0] + ".%%", now, "invalid"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.page.checkCSRF(tc.browser, tc.token, tc.now)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("checkCSRF: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("checkCSRF error %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWithoutParam(t *testing.T) {
+	for _, tc := range []struct{ query, want string }{
+		{"", ""},
+		{"lv_csrf=1", ""},
+		{"a=1&lv_csrf=2&b=3", "a=1&b=3"},
+		{"lv_csrf&a=1", "a=1"},
+		{"lv_csrfx=1", "lv_csrfx=1"},
+	} {
+		if got := withoutParam(tc.query, csrfParam); got != tc.want {
+			t.Errorf("withoutParam(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package view
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// DownloadExpiry is the time the browser has to start a download
+var DownloadExpiry = time.Minute
+
+type download struct {
+	name        string
+	contentType string
+	reader      io.Reader
+	session     *Session
+	timer       *time.Timer
+}
+
+var (
+	muDownloads sync.Mutex
+	downloads   map[string]*download = make(map[string]*download)
+)
+
+// Download send the content of r to the browser as the file name. The file is served
+// once by a url of the page only known by the connection, and only to the browser that
+// opened the connection (the cookie lv_csrf). The url expires after
+// DownloadExpiry or when the connection is closed. When r is an io.Closer it is closed
+func (cw *ComponentDriver[T]) Download(name string, contentType string, r io.Reader) error {
+	s := sessionFor(cw.Conn)
+	if s == nil {
+		closeReader(r)
+		return fmt.Errorf("download %s: the component is not connected", name)
+	}
+	page := s.Page()
+	if page == nil {
+		closeReader(r)
+		return fmt.Errorf("download %s: no page", name)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	token := uuid.NewString()
+	d := &download{name: name, contentType: contentType, reader: r, session: s}
+	muDownloads.Lock()
+	downloads[token] = d
+	d.timer = time.AfterFunc(DownloadExpiry, func() {
+		if d := takeDownload(token); d != nil {
+			closeReader(d.reader)
+		}
+	})
+	muDownloads.Unlock()
+
+	cw.send(map[string]interface{}{"type": "download", "value": page.Path + "lv_download/" + token, "name": name})
+	return nil
+}
+
+// takeDownload remove the download of token and return it
+func takeDownload(token string) *download {
+	muDownloads.Lock()
+	defer muDownloads.Unlock()
+	d, ok := downloads[token]
+	if !ok {
+		return nil
+	}
+	delete(downloads, token)
+	d.timer.Stop()
+	return d
+}
+
+// claimDownload take the download of token when browser is the one of its session, the
+// status is 404 without download and 403 for other browsers, the download is kept then
+func claimDownload(token string, browser string) (*download, int) {
+	muDownloads.Lock()
+	defer muDownloads.Unlock()
+	d, ok := downloads[token]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	if browser == "" || browser != d.session.browser {
+		return nil, http.StatusForbidden
+	}
+	delete(downloads, token)
+	d.timer.Stop()
+	return d, http.StatusOK
+}
+
+// cancelDownloads discard the downloads of s not started, when the connection is closed
+func (s *Session) cancelDownloads() {
+	muDownloads.Lock()
+	list := make([]*download, 0)
+	for token, d := range downloads {
+		if d.session == s {
+			delete(downloads, token)
+			d.timer.Stop()
+			list = append(list, d)
+		}
+	}
+	muDownloads.Unlock()
+	for _, d := range list {
+		closeReader(d.reader)
+	}
+}
+
+func closeReader(r io.Reader) {
+	if c, ok := r.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// serveDownload is the route lv_download/:token of the pages
+func serveDownload(c *fiber.Ctx) error {
+	d, status := claimDownload(c.Params("token"), c.Cookies(csrfCookie))
+	if d == nil {
+		return c.SendStatus(status)
+	}
+	c.Set("Content-Type", d.contentType)
+	c.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": d.name}))
+	c.Set("Cache-Control", "no-store")
+	c.Set("X-Content-Type-Options", "nosniff")
+	// the stream is closed when it is sent
+	return c.SendStream(d.reader)
+}
@@ -0,0 +1,130 @@
+package view
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fasthttpws "github.com/fasthttp/websocket"
+)
+
+type exportFile struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (f *exportFile) Close() error {
+	f.closed.Store(true)
+	return nil
+}
+
+type exporter struct {
+	*ComponentDriver[*exporter]
+	File *exportFile
+}
+
+func (c *exporter) GetDriver() LiveDriver { return c }
+func (c *exporter) Start()                { c.Commit() }
+func (c *exporter) GetTemplate() string   { return `<div id="{{.IdComponent}}"></div>` }
+
+func (c *exporter) Export(data interface{}) {
+	c.File = &exportFile{Reader: strings.NewReader("id,name\n1,ana\n")}
+	if err := c.Download("tasks.csv", "text/csv", c.File); err != nil {
+		panic(err)
+	}
+}
+
+// startDownload dial a page whose component download a file on the event Export, it
+// return the component and a function that start a download and return its url
+func startDownload(t *testing.T) (*exporter, func() string) {
+	t.Helper()
+	c := &exporter{}
+	header := http.Header{}
+	header.Set("Cookie", csrfCookie+"=browser1")
+	_, conn := dialPageWith(t, &PageControl{}, header, func() LiveDriver {
+		return NewDriver("exporter", c)
+	})
+	messages := readMessages(conn)
+	messages()
+	base := "http://" + conn.RemoteAddr().String()
+	return c, func() string {
+		t.Helper()
+		if err := conn.WriteMessage(fasthttpws.TextMessage, []byte(`{"type":"data","id":"exporter","event":"Export"}`)); err != nil {
+			t.Fatal(err)
+		}
+		for _, msg := range messages() {
+			if msg["type"] == "download" {
+				if msg["name"] != "tasks.csv" {
+					t.Errorf("download name %v, want tasks.csv", msg["name"])
+				}
+				return base + msg["value"].(string)
+			}
+		}
+		t.Fatal("the download was not sent")
+		return ""
+	}
+}
+
+func getDownload(t *testing.T, url string, browser string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if browser != "" {
+		req.AddCookie(&http.Cookie{Name: csrfCookie, Value: browser})
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestDownloadOnce(t *testing.T) {
+	c, start := startDownload(t)
+	url := start()
+
+	// the url is only valid in the browser of the connection, the download is kept
+	if status, _ := getDownload(t, url, "browser2"); status != http.StatusForbidden {
+		t.Errorf("status %d for another browser, want 403", status)
+	}
+	if status, _ := getDownload(t, url, ""); status != http.StatusForbidden {
+		t.Errorf("status %d without cookie, want 403", status)
+	}
+	status, body := getDownload(t, url, "browser1")
+	if status != http.StatusOK || body != "id,name\n1,ana\n" {
+		t.Fatalf("download status %d body %q", status, body)
+	}
+
+	// the token can not be used again
+	if status, _ := getDownload(t, url, "browser1"); status != http.StatusNotFound {
+		t.Errorf("status %d when the url is reused, want 404", status)
+	}
+	if !c.File.closed.Load() {
+		t.Error("the file was not closed after the download")
+	}
+}
+
+func TestDownloadExpiry(t *testing.T) {
+	defer func(expiry time.Duration) { DownloadExpiry = expiry }(DownloadExpiry)
+	DownloadExpiry = 50 * time.Millisecond
+	c, start := startDownload(t)
+	url := start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !c.File.closed.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("the file of the expired download was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status, _ := getDownload(t, url, "browser1"); status != http.StatusNotFound {
+		t.Errorf("status %d after the expiry, want 404", status)
+	}
+}
@@ -0,0 +1,281 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TemplateFuncer is implemented by components with their own template functions, the
+// functions are bound when the template is parsed, once by component type, so they must
+// not depend on the instance
+type TemplateFuncer interface {
+	TemplateFuncs() template.FuncMap
+}
+
+// RegisterFunc add fn to the functions available in every template of DefaultApp
+func RegisterFunc(name string, fn interface{}) {
+	DefaultApp.RegisterFunc(name, fn)
+}
+
+// RegisterFunc add fn to the functions available in every template of the app
+func (a *App) RegisterFunc(name string, fn interface{}) {
+	a.mu.Lock()
+	a.funcs[name] = fn
+	a.mu.Unlock()
+	// the templates already parsed do not know the function
+	clearTemplates(a)
+}
+
+// funcMap return FuncMapTemplate with the functions registered in the app
+func (a *App) funcMap() template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, fn := range FuncMapTemplate {
+		funcs[name] = fn
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for name, fn := range a.funcs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// templateFuncs return the functions of a template of app rendered in page for c, page
+// can be nil
+func templateFuncs(c Component, app *App, page *PageControl) template.FuncMap {
+	funcs := app.funcMap()
+	if page != nil {
+		for name, fn := range page.Funcs {
+			funcs[name] = fn
+		}
+	}
+	if f, ok := c.(TemplateFuncer); ok {
+		for name, fn := range f.TemplateFuncs() {
+			funcs[name] = fn
+		}
+	}
+	return funcs
+}
+
+// formatDate format t with layout, 2006-01-02 by default
+func formatDate(t interface{}, layout ...string) string {
+	l := "2006-01-02"
+	if len(layout) > 0 {
+		l = layout[0]
+	}
+	switch v := t.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(l)
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return ""
+		}
+		return v.Format(l)
+	}
+	return fmt.Sprint(t)
+}
+
+func toFloat(n interface{}) (float64, bool) {
+	v := reflect.ValueOf(n)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatNumber format n with decimals digits and thousands separated by commas
+func formatNumber(n interface{}, decimals ...int) string {
+	f, ok := toFloat(n)
+	if !ok {
+		return fmt.Sprint(n)
+	}
+	d := 0
+	if len(decimals) > 0 && decimals[0] > 0 {
+		d = decimals[0]
+	}
+	s := strconv.FormatFloat(math.Abs(f), 'f', d, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if fracPart != "" {
+		b.WriteByte('.')
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
+// formatCurrency format amount with two decimals after symbol, for example $1,234.50
+func formatCurrency(amount interface{}, symbol ...string) string {
+	s := "$"
+	if len(symbol) > 0 {
+		s = symbol[0]
+	}
+	n := formatNumber(amount, 2)
+	if strings.HasPrefix(n, "-") {
+		return "-" + s + n[1:]
+	}
+	return s + n
+}
+
+// equal compare two values of any comparable type, the numbers are compared by value
+func equal(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok && reflect.ValueOf(a).Kind() != reflect.String {
+		if fb, ok := toFloat(b); ok && reflect.ValueOf(b).Kind() != reflect.String {
+			return fa == fb
+		}
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta == nil || !ta.Comparable() {
+		return ta == nil
+	}
+	return a == b
+}
+
+// eq return true when a is equal to any of values
+func eq(a interface{}, values ...interface{}) bool {
+	for _, b := range values {
+		if equal(a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// in return true when item is an element of a slice or array, a key of a map or a
+// substring of a string
+func in(item interface{}, collection interface{}) bool {
+	v := reflect.ValueOf(collection)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if equal(item, v.Index(i).Interface()) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if equal(item, k.Interface()) {
+				return true
+			}
+		}
+	case reflect.String:
+		return strings.Contains(v.String(), fmt.Sprint(item))
+	}
+	return false
+}
+
+// classList join the classes enabled, it receives a map[string]bool or pairs of class
+// and condition: classList "btn" true "active" .Active
+func classList(args ...interface{}) string {
+	var classes []string
+	if len(args) == 1 {
+		if m, ok := args[0].(map[string]bool); ok {
+			for class, enabled := range m {
+				if enabled {
+					classes = append(classes, class)
+				}
+			}
+			sort.Strings(classes)
+			return strings.Join(classes, " ")
+		}
+	}
+	for i := 0; i < len(args); i += 2 {
+		enabled := true
+		if i+1 < len(args) {
+			enabled = truth(args[i+1])
+		}
+		if class := fmt.Sprint(args[i]); enabled && class != "" {
+			classes = append(classes, class)
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+func truth(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	return !reflect.ValueOf(v).IsZero()
+}
+
+// toJSON marshal v, the result is safe inside a script
+func toJSON(v interface{}) (template.JS, error) {
+	b, err := json.Marshal(v)
+	return template.JS(b), err
+}
+
+// pluralize return singular when n is 1, plural otherwise
+func pluralize(n interface{}, singular, plural string) string {
+	if f, ok := toFloat(n); ok && f == 1 {
+		return singular
+	}
+	return plural
+}
+
+// attr build the attribute name="value" with value escaped, the names refused by Attr of
+// the builder, style and the urls (href, src, action...) are refused because they are not
+// plain text
+func attr(name string, value interface{}) (template.HTMLAttr, error) {
+	lower := strings.ToLower(name)
+	if err := checkAttrName(name); err != nil {
+		return "", fmt.Errorf("attr: %w", err)
+	}
+	if lower == "style" || urlAttributes[lower] {
+		return "", fmt.Errorf("attr: %q is not a safe attribute", name)
+	}
+	return template.HTMLAttr(name + `="` + html.EscapeString(fmt.Sprint(value)) + `"`), nil
+}
+
+// attrs build the attributes of a map with attr, in alphabetical order
+func attrs(m map[string]interface{}) (template.HTMLAttr, error) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		a, err := attr(name, m[name])
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, string(a))
+	}
+	return template.HTMLAttr(strings.Join(parts, " ")), nil
+}
@@ -0,0 +1,106 @@
+package view
+
+import "testing"
+
+func TestFormatNumber(t *testing.T) {
+	f := 1234.5
+	for _, tc := range []struct {
+		n        interface{}
+		decimals []int
+		want     string
+	}{
+		{0, nil, "0"},
+		{999, nil, "999"},
+		{1000, nil, "1,000"},
+		{1234567, nil, "1,234,567"},
+		{-1234567, nil, "-1,234,567"},
+		{uint8(200), nil, "200"},
+		{1234.5678, []int{2}, "1,234.57"},
+		{1234.6, nil, "1,235"},
+		{&f, []int{1}, "1,234.5"},
+		{"98765.4", []int{1}, "98,765.4"},
+		{-0.001, []int{2}, "0.00"},
+		{"abc", nil, "abc"},
+	} {
+		if got := formatNumber(tc.n, tc.decimals...); got != tc.want {
+			t.Errorf("formatNumber(%v, %v) = %q, want %q", tc.n, tc.decimals, got, tc.want)
+		}
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	for _, tc := range []struct {
+		amount interface{}
+		symbol []string
+		want   string
+	}{
+		{1234.5, nil, "$1,234.50"},
+		{0, nil, "$0.00"},
+		{-1234.5, nil, "-$1,234.50"},
+		{1000000, []string{"€"}, "€1,000,000.00"},
+	} {
+		if got := formatCurrency(tc.amount, tc.symbol...); got != tc.want {
+			t.Errorf("formatCurrency(%v, %v) = %q, want %q", tc.amount, tc.symbol, got, tc.want)
+		}
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	for _, tc := range []struct {
+		n    interface{}
+		want string
+	}{
+		{0, "items"},
+		{1, "item"},
+		{int64(1), "item"},
+		{1.0, "item"},
+		{2, "items"},
+		{"1", "item"},
+		{nil, "items"},
+	} {
+		if got := pluralize(tc.n, "item", "items"); got != tc.want {
+			t.Errorf("pluralize(%v) = %q, want %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestClassList(t *testing.T) {
+	for _, tc := range []struct {
+		args []interface{}
+		want string
+	}{
+		{nil, ""},
+		{[]interface{}{"btn", true, "active", false}, "btn"},
+		{[]interface{}{"btn", true, "active", 1, "hidden", ""}, "btn active"},
+		{[]interface{}{"btn"}, "btn"},
+		{[]interface{}{"", true, "x", "yes"}, "x"},
+		{[]interface{}{map[string]bool{"b": true, "a": true, "c": false}}, "a b"},
+	} {
+		if got := classList(tc.args...); got != tc.want {
+			t.Errorf("classList(%v) = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestIn(t *testing.T) {
+	for _, tc := range []struct {
+		item       interface{}
+		collection interface{}
+		want       bool
+	}{
+		{2, []int{1, 2, 3}, true},
+		{4, []int{1, 2, 3}, false},
+		{int64(2), []float64{1, 2}, true},
+		{"2", []int{1, 2}, false},
+		{"b", [2]string{"a", "b"}, true},
+		{"k", map[string]int{"k": 1}, true},
+		{1, map[string]int{"k": 1}, false},
+		{"ell", "hello", true},
+		{"x", "hello", false},
+		{1, nil, false},
+	} {
+		if got := in(tc.item, tc.collection); got != tc.want {
+			t.Errorf("in(%v, %v) = %v, want %v", tc.item, tc.collection, got, tc.want)
+		}
+	}
+}
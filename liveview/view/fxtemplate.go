@@ -1,14 +1,32 @@
 package view
 
 import (
-	"text/template"
+	"html/template"
 )
 
+// SafeHTML is markup trusted by the developer, it is rendered without escaping. Never
+// convert input of the users to SafeHTML
+type SafeHTML = template.HTML
+
 var (
+	// FuncMapTemplate has the functions available in every template of every app, use
+	// RegisterFunc or App.RegisterFunc to add one
 	FuncMapTemplate = template.FuncMap{
-		"mount": func(id string) string {
-			return "<span id='mount_span_" + id + "'></span>"
+		"mount": func(id string) template.HTML {
+			return template.HTML("<span id='mount_span_" + template.HTMLEscapeString(id) + "'></span>")
 		},
-		"eqInt": func(value1, value2 int) bool { return value1 == value2 },
+		"eqInt":     func(value1, value2 int) bool { return value1 == value2 },
+		"mountEach": mountEach,
+
+		"formatDate":     formatDate,
+		"formatNumber":   formatNumber,
+		"formatCurrency": formatCurrency,
+		"eq":             eq,
+		"in":             in,
+		"classList":      classList,
+		"json":           toJSON,
+		"pluralize":      pluralize,
+		"attr":           attr,
+		"attrs":          attrs,
 	}
 )
@@ -0,0 +1,83 @@
+package view
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type hooked struct {
+	*ComponentDriver[*hooked]
+}
+
+func (c *hooked) GetDriver() LiveDriver { return c }
+func (c *hooked) Start()                { c.Commit() }
+func (c *hooked) GetTemplate() string {
+	return `<div id="{{.IdComponent}}" lv-hook="Chart"></div>`
+}
+
+// Point is sent by pushEvent of the hook, the reply is pushed back
+func (c *hooked) Point(data interface{}) {
+	c.PushEvent("point", map[string]interface{}{"received": data})
+}
+
+func (c *hooked) Eval(data interface{}) {
+	c.EvalScript("alert(1)")
+}
+
+// hookedMessages dial a page with the component hooked and return a function that send
+// an event to it and return the messages received
+func hookedMessages(t *testing.T, pc *PageControl) func(event string, data interface{}) []map[string]interface{} {
+	t.Helper()
+	_, conn := dialPage(t, pc, func() LiveDriver {
+		return NewDriver("hooked", &hooked{})
+	})
+	messages := readMessages(conn)
+	messages()
+	return func(event string, data interface{}) []map[string]interface{} {
+		t.Helper()
+		msg := map[string]interface{}{"type": "data", "id": "hooked", "event": event, "data": data}
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+		return messages()
+	}
+}
+
+func TestPushEvent(t *testing.T) {
+	send := hookedMessages(t, &PageControl{})
+	msgs := send("Point", map[string]interface{}{"x": 1.0, "y": 2.0})
+	// the event goes to the hooks inside the element where the component is mounted
+	want := map[string]interface{}{
+		"type":  "hook_event",
+		"id":    "content",
+		"event": "point",
+		"value": map[string]interface{}{"received": map[string]interface{}{"x": 1.0, "y": 2.0}},
+	}
+	if len(msgs) != 1 || !reflect.DeepEqual(msgs[0], want) {
+		t.Fatalf("messages %v, want %v", msgs, want)
+	}
+}
+
+func TestDisableEval(t *testing.T) {
+	send := hookedMessages(t, &PageControl{})
+	if msgs := send("Eval", nil); len(msgs) != 1 || msgs[0]["type"] != "script" || msgs[0]["value"] != "alert(1)" {
+		t.Fatalf("messages %v, want the script", msgs)
+	}
+
+	send = hookedMessages(t, &PageControl{DisableEval: true})
+	if msgs := send("Eval", nil); len(msgs) != 0 {
+		t.Fatalf("EvalScript sent %v with DisableEval", msgs)
+	}
+
+	// the client is told to refuse the scripts
+	meta := `<meta name="liveview-eval" content="off"/>`
+	for _, disabled := range []bool{false, true} {
+		out := renderShell(t, &PageControl{App: newApp(fiber.New(), NewHub()), DisableEval: disabled})
+		if strings.Contains(out, meta) != disabled {
+			t.Errorf("DisableEval %v: the shell includes the meta %v", disabled, !disabled)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package view
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Hub is the registry of the layouts alive in an App, it delivers the broadcasts to
+// their HandlerEventIn
+type Hub struct {
+	mu      *sync.RWMutex
+	layouts map[string]*Layout
+}
+
+// NewHub create an empty hub
+func NewHub() *Hub {
+	return &Hub{
+		mu:      &sync.RWMutex{},
+		layouts: make(map[string]*Layout),
+	}
+}
+
+// Add register the layout with its UUID
+func (h *Hub) Add(l *Layout) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if old, ok := h.layouts[l.UUID]; ok && old != l {
+		fmt.Println("Layout ya existe:", l.UUID, "se reemplaza en el hub")
+	}
+	h.layouts[l.UUID] = l
+	l.hub.Store(h)
+}
+
+// Get return the layout registered with uid
+func (h *Hub) Get(uid string) (*Layout, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	l, ok := h.layouts[uid]
+	return l, ok
+}
+
+// Delete remove the layout registered with uid
+func (h *Hub) Delete(uid string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.layouts[uid]; ok {
+		delete(h.layouts, uid)
+		fmt.Println("Layout eliminado:", uid)
+	}
+}
+
+// remove delete l from the hub, the layout registered later with the same uid is kept
+func (h *Hub) remove(l *Layout) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.layouts[l.UUID] == l {
+		delete(h.layouts, l.UUID)
+		fmt.Println("Layout eliminado:", l.UUID)
+	}
+}
+
+// Len return the number of layouts alive
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.layouts)
+}
+
+// SendToAll deliver msg to every layout of the hub
+func (h *Hub) SendToAll(msg interface{}) {
+	h.mu.RLock() // Lectura concurrente segura
+	layoutsCopy := make([]*Layout, 0, len(h.layouts))
+	for _, v := range h.layouts {
+		layoutsCopy = append(layoutsCopy, v)
+	}
+	h.mu.RUnlock() // Liberar el bloqueo antes de operar
+
+	for _, v := range layoutsCopy {
+		v.HandlerEventIn(msg)
+	}
+}
+
+// SendTo deliver msg to the layouts registered with uuids
+func (h *Hub) SendTo(msg interface{}, uuids ...string) {
+	layoutsCopy := make([]*Layout, 0, len(uuids))
+	h.mu.RLock()
+	for _, uid := range uuids {
+		if v, ok := h.layouts[uid]; ok {
+			layoutsCopy = append(layoutsCopy, v)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, v := range layoutsCopy {
+		v.HandlerEventIn(msg)
+	}
+}
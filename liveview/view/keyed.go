@@ -0,0 +1,296 @@
+package view
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RegisterComponent register in DefaultApp the factory used by mountEach to create the
+// components of a list
+func RegisterComponent[T Component](name string, factory func() T) {
+	RegisterComponentIn(DefaultApp, name, factory)
+}
+
+// RegisterComponentIn register in app the factory used by mountEach to create the
+// components of a list
+func RegisterComponentIn[T Component](app *App, name string, factory func() T) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.components[name] = func(id string) LiveDriver {
+		c := factory()
+		NewDriver(id, c)
+		return c.GetDriver()
+	}
+}
+
+// component return the factory registered with name
+func (a *App) component(name string) (func(id string) LiveDriver, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	factory, ok := a.components[name]
+	return factory, ok
+}
+
+// keyedList is the state of one mountEach of a component
+type keyedList struct {
+	children map[string]LiveDriver
+}
+
+// eachUpdate is the work left by mountEach until the fill of the parent is sent
+type eachUpdate struct {
+	containerID string
+	ids         []string
+	created     []LiveDriver
+	changed     []LiveDriver
+	// kept are the children reused, changed or not
+	kept    []LiveDriver
+	removed []LiveDriver
+}
+
+type eachMounter interface {
+	mountEach(items interface{}, name string, keyField string) (string, error)
+}
+
+// mountEach render a container with one component of kind name for each item, the
+// components are reused between renders by key. It is used in templates as
+// {{mountEach . .Items "todo_item" ".ID"}}. The template functions do not receive the
+// component being rendered, so it is the first argument, and the key is a string with
+// the path of the field (or map key) of the items, like ".ID" or ".Owner.Name", because
+// an expression .Key would be evaluated on the component and not on each item. Without
+// keyField the key is the index of the slice or the key of the map. The lists of the
+// same name in one template are numbered in render order
+func mountEach(parent Component, items interface{}, name string, keyField ...string) (SafeHTML, error) {
+	m, ok := parent.GetDriver().(eachMounter)
+	if !ok {
+		return "", fmt.Errorf("mountEach: %T can not mount components", parent)
+	}
+	field := ""
+	if len(keyField) > 0 {
+		field = keyField[0]
+	}
+	html, err := m.mountEach(items, name, field)
+	return SafeHTML(html), err
+}
+
+func (cw *ComponentDriver[T]) mountEach(items interface{}, name string, keyField string) (string, error) {
+	factory, ok := appFor(cw.Conn).component(name)
+	if !ok {
+		return "", fmt.Errorf("mountEach: component %q is not registered", name)
+	}
+	keys, values, err := eachItems(items, keyField)
+	if err != nil {
+		return "", err
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			return "", fmt.Errorf("mountEach: duplicated key %q in %s", key, name)
+		}
+		seen[key] = true
+	}
+
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	if cw.lists == nil {
+		cw.lists = make(map[string]*keyedList)
+		cw.eachChildren = make(map[string]string)
+	}
+	if cw.eachCount == nil {
+		cw.eachCount = make(map[string]int)
+	}
+	cw.eachCount[name]++
+	// the name and the keys are escaped so ":" only separates the number of the list and
+	// the key, the lists and their children never share an id
+	containerID := "each_" + cw.GetIDComponet() + "_" + url.QueryEscape(name)
+	if n := cw.eachCount[name]; n > 1 {
+		containerID += ":" + strconv.Itoa(n)
+	}
+	list, ok := cw.lists[containerID]
+	if !ok {
+		list = &keyedList{children: make(map[string]LiveDriver)}
+		cw.lists[containerID] = list
+	}
+
+	update := &eachUpdate{containerID: containerID}
+	next := make(map[string]LiveDriver, len(keys))
+	for i, key := range keys {
+		child, ok := list.children[key]
+		if ok {
+			if !sameItem(child.GetData(), values[i]) {
+				child.SetData(values[i])
+				update.changed = append(update.changed, child)
+			}
+			update.kept = append(update.kept, child)
+		} else {
+			// the key is escaped, "a b" and "a_b" must not share a component
+			idComponent := containerID + ":" + url.QueryEscape(key)
+			child = factory(idComponent)
+			child.SetID("mount_span_" + idComponent)
+			child.SetParent(cw)
+			child.SetData(values[i])
+			cw.componentsDrivers[child.GetID()] = child
+			cw.eachChildren[child.GetID()] = containerID
+			update.created = append(update.created, child)
+		}
+		next[key] = child
+		update.ids = append(update.ids, child.GetID())
+	}
+	for key, child := range list.children {
+		if _, ok := next[key]; !ok {
+			delete(cw.componentsDrivers, child.GetID())
+			delete(cw.eachChildren, child.GetID())
+			update.removed = append(update.removed, child)
+		}
+	}
+	list.children = next
+	cw.pendingEach = append(cw.pendingEach, update)
+	return `<div id="` + template.HTMLEscapeString(containerID) + `" lv-each></div>`, nil
+}
+
+// sameItem return true when the item of a child did not change. The pointers, maps and
+// slices can be changed in place, so they are always rendered again
+func sameItem(old interface{}, item interface{}) bool {
+	switch reflect.ValueOf(item).Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		return false
+	}
+	return reflect.DeepEqual(old, item)
+}
+
+// flushEach send the order of the lists rendered in the last Commit and start, update
+// or destroy their components. When the node of the component was replaced its lists
+// are new and empty in the browser, so every child is rendered again
+func (cw *ComponentDriver[T]) flushEach(replaced bool) {
+	cw.muTree.Lock()
+	updates := cw.pendingEach
+	cw.pendingEach = nil
+	mounted := cw.mounted
+	cw.muTree.Unlock()
+
+	for _, u := range updates {
+		cw.send(map[string]interface{}{"type": "each", "id": u.containerID, "value": u.ids})
+		for _, c := range u.removed {
+			c.Destroy()
+		}
+		if !mounted {
+			// StartDriver will start the created children
+			continue
+		}
+		for _, c := range u.created {
+			c.StartDriver(cw.Conn, cw.DriversPage, cw.channelIn)
+		}
+		if replaced {
+			for _, c := range u.kept {
+				if c.IsMounted() {
+					recommit(c)
+				}
+			}
+			continue
+		}
+		for _, c := range u.changed {
+			if c.IsMounted() {
+				c.Commit()
+			}
+		}
+	}
+}
+
+// isEachChild return true when the child belongs to a list, its node survives the
+// renders of the parent so it does not need to be committed again, unless the node of
+// the parent is replaced
+func (cw *ComponentDriver[T]) isEachChild(id string) bool {
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	_, ok := cw.eachChildren[id]
+	return ok
+}
+
+// eachItems return the keys and the items of a slice, array or map in render order
+func eachItems(items interface{}, keyField string) ([]string, []interface{}, error) {
+	v := reflect.ValueOf(items)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil, nil
+		}
+		v = v.Elem()
+	}
+	var keys []string
+	var values []interface{}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil, nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			key := strconv.Itoa(i)
+			if keyField != "" {
+				k, err := itemKey(v.Index(i), keyField)
+				if err != nil {
+					return nil, nil, err
+				}
+				key = k
+			}
+			keys = append(keys, key)
+			values = append(values, v.Index(i).Interface())
+		}
+	case reflect.Map:
+		mapKeys := v.MapKeys()
+		sort.Slice(mapKeys, func(i, j int) bool {
+			return fmt.Sprint(mapKeys[i].Interface()) < fmt.Sprint(mapKeys[j].Interface())
+		})
+		for _, mk := range mapKeys {
+			key := fmt.Sprint(mk.Interface())
+			if keyField != "" {
+				k, err := itemKey(v.MapIndex(mk), keyField)
+				if err != nil {
+					return nil, nil, err
+				}
+				key = k
+			}
+			keys = append(keys, key)
+			values = append(values, v.MapIndex(mk).Interface())
+		}
+	default:
+		return nil, nil, fmt.Errorf("mountEach: can not range over %s", v.Type())
+	}
+	return keys, values, nil
+}
+
+// itemKey return the key of item, keyField is a path like ".ID" or ".Owner.Name", the
+// dot of the start is optional
+func itemKey(item reflect.Value, keyField string) (string, error) {
+	key := item
+	for _, name := range strings.Split(strings.TrimPrefix(keyField, "."), ".") {
+		for key.Kind() == reflect.Pointer || key.Kind() == reflect.Interface {
+			if key.IsNil() {
+				return "", fmt.Errorf("mountEach: nil item has not key %s", keyField)
+			}
+			key = key.Elem()
+		}
+		next := reflect.Value{}
+		switch key.Kind() {
+		case reflect.Struct:
+			next = key.FieldByName(name)
+		case reflect.Map:
+			if key.Type().Key().Kind() == reflect.String {
+				next = key.MapIndex(reflect.ValueOf(name).Convert(key.Type().Key()))
+			}
+		}
+		if !next.IsValid() {
+			return "", fmt.Errorf("mountEach: item %s has not key %s", item.Type(), keyField)
+		}
+		key = next
+	}
+	for key.Kind() == reflect.Pointer || key.Kind() == reflect.Interface {
+		if key.IsNil() {
+			return "", fmt.Errorf("mountEach: nil key %s", keyField)
+		}
+		key = key.Elem()
+	}
+	return fmt.Sprint(key.Interface()), nil
+}
@@ -0,0 +1,213 @@
+package view
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	fasthttpws "github.com/fasthttp/websocket"
+)
+
+type eachRow struct {
+	*ComponentDriver[*eachRow]
+}
+
+func (c *eachRow) GetDriver() LiveDriver { return c }
+func (c *eachRow) Start()                { c.Commit() }
+func (c *eachRow) GetTemplate() string   { return `<span id="{{.IdComponent}}"></span>` }
+
+type eachItem struct {
+	ID    string
+	Title string
+	Owner struct{ Name string }
+}
+
+// eachStep render the list and return the update left for the fill of the parent
+func eachStep(t *testing.T, d *ComponentDriver[*fuzzForm], items interface{}, keyField string) *eachUpdate {
+	t.Helper()
+	if _, err := d.mountEach(items, "each_row", keyField); err != nil {
+		t.Fatal(err)
+	}
+	d.muTree.Lock()
+	defer d.muTree.Unlock()
+	d.eachCount = nil
+	update := d.pendingEach[len(d.pendingEach)-1]
+	d.pendingEach = nil
+	return update
+}
+
+func idsOf(drivers []LiveDriver) []string {
+	ids := []string{}
+	for _, d := range drivers {
+		ids = append(ids, d.GetID())
+	}
+	return ids
+}
+
+func TestMountEach(t *testing.T) {
+	RegisterComponent("each_row", func() *eachRow { return &eachRow{} })
+	d := NewDriver("list", &fuzzForm{})
+	row := func(id string) string { return "mount_span_each_list_each_row:" + id }
+
+	first := eachStep(t, d, []eachItem{{ID: "a"}, {ID: "b"}, {ID: "c"}}, ".ID")
+	if want := []string{row("a"), row("b"), row("c")}; !reflect.DeepEqual(first.ids, want) {
+		t.Fatalf("ids %v, want %v", first.ids, want)
+	}
+	if len(first.created) != 3 {
+		t.Fatalf("%d created, want 3", len(first.created))
+	}
+	a := d.GetDriverById("each_list_each_row:a")
+
+	// reorder and remove
+	update := eachStep(t, d, []eachItem{{ID: "c"}, {ID: "a", Title: "A"}}, "ID")
+	if want := []string{row("c"), row("a")}; !reflect.DeepEqual(update.ids, want) {
+		t.Fatalf("ids %v, want %v", update.ids, want)
+	}
+	if len(update.created) != 0 {
+		t.Fatalf("created %v, the components must be reused", idsOf(update.created))
+	}
+	if got := idsOf(update.removed); !reflect.DeepEqual(got, []string{row("b")}) {
+		t.Fatalf("removed %v, want %v", got, []string{row("b")})
+	}
+	if got := idsOf(update.changed); !reflect.DeepEqual(got, []string{row("a")}) {
+		t.Fatalf("changed %v, want %v", got, []string{row("a")})
+	}
+	if d.GetDriverById("each_list_each_row:a") != a {
+		t.Fatal("the component of the key a was replaced")
+	}
+	if d.isEachChild(row("b")) {
+		t.Fatal("the removed component is still a child")
+	}
+
+	// the same values are not rendered again
+	update = eachStep(t, d, []eachItem{{ID: "c"}, {ID: "a", Title: "A"}}, "ID")
+	if len(update.changed) != 0 {
+		t.Fatalf("changed %v without changes", idsOf(update.changed))
+	}
+}
+
+func TestMountEachPointers(t *testing.T) {
+	RegisterComponent("each_row", func() *eachRow { return &eachRow{} })
+	d := NewDriver("pointers", &fuzzForm{})
+	items := []*eachItem{{ID: "a"}, {ID: "b"}}
+	eachStep(t, d, items, "ID")
+	items[0].Title = "changed in place"
+	update := eachStep(t, d, items, "ID")
+	if len(update.changed) != 2 {
+		t.Fatalf("changed %v, the pointers must be rendered again", idsOf(update.changed))
+	}
+}
+
+func TestMountEachSameName(t *testing.T) {
+	RegisterComponent("each_row", func() *eachRow { return &eachRow{} })
+	d := NewDriver("twice", &fuzzForm{})
+	first, err := d.mountEach([]string{"x"}, "each_row", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := d.mountEach([]string{"y"}, "each_row", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("the two lists share the container %s", first)
+	}
+	if len(d.pendingEach) != 2 || len(d.pendingEach[1].removed) != 0 {
+		t.Fatal("the second list removed the children of the first one")
+	}
+	if !strings.Contains(second, `id="each_twice_each_row:2"`) {
+		t.Fatalf("second container %s", second)
+	}
+}
+
+func TestEachItemsKeys(t *testing.T) {
+	owner := eachItem{ID: "1"}
+	owner.Owner.Name = "ana"
+	for _, tc := range []struct {
+		name     string
+		items    interface{}
+		keyField string
+		keys     []string
+		err      bool
+	}{
+		{"index", []string{"a", "b"}, "", []string{"0", "1"}, false},
+		{"map", map[string]int{"b": 1, "a": 2}, "", []string{"a", "b"}, false},
+		{"field", []eachItem{{ID: "x"}}, "ID", []string{"x"}, false},
+		{"dot field", []eachItem{{ID: "x"}}, ".ID", []string{"x"}, false},
+		{"path", []eachItem{owner}, ".Owner.Name", []string{"ana"}, false},
+		{"map key", []map[string]string{{"id": "k"}}, ".id", []string{"k"}, false},
+		{"pointer", []*eachItem{{ID: "p"}}, ".ID", []string{"p"}, false},
+		{"nil", nil, "", nil, false},
+		{"missing field", []eachItem{{ID: "x"}}, ".Missing", nil, true},
+		{"nil item", []*eachItem{nil}, ".ID", nil, true},
+		{"not a list", 12, "", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, _, err := eachItems(tc.items, tc.keyField)
+			if (err != nil) != tc.err {
+				t.Fatalf("eachItems error %v", err)
+			}
+			if !tc.err && !reflect.DeepEqual(keys, tc.keys) {
+				t.Fatalf("keys %v, want %v", keys, tc.keys)
+			}
+		})
+	}
+}
+
+type eachPage struct {
+	*ComponentDriver[*eachPage]
+	Items []eachItem
+}
+
+func (c *eachPage) GetDriver() LiveDriver { return c }
+func (c *eachPage) Start()                {}
+func (c *eachPage) GetTemplate() string {
+	return `<div id="{{.IdComponent}}">{{mountEach . .Items "each_row" ".ID"}}</div>`
+}
+
+// Show register the component of the rows in the app of the session and render the list
+func (c *eachPage) Show(data interface{}) {
+	RegisterComponentIn(appFor(c.Conn), "each_row", func() *eachRow { return &eachRow{} })
+	c.Commit()
+}
+
+func (c *eachPage) Refresh(data interface{}) { c.Commit() }
+
+// Replaced render the page as its parent does after the fill of the parent
+func (c *eachPage) Replaced(data interface{}) { recommit(c.ComponentDriver) }
+
+func TestEachReplacedParent(t *testing.T) {
+	_, conn := dialPage(t, &PageControl{}, func() LiveDriver {
+		return NewDriver("each_page", &eachPage{Items: []eachItem{{ID: "a"}, {ID: "b"}}})
+	})
+	messages := readMessages(conn)
+	messages()
+	event := func(name string) map[string]int {
+		t.Helper()
+		msg := `{"type":"data","id":"each_page","event":"` + name + `"}`
+		if err := conn.WriteMessage(fasthttpws.TextMessage, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		fills := map[string]int{}
+		for _, msg := range messages() {
+			if msg["type"] == "fill" {
+				fills[msg["id"].(string)]++
+			}
+		}
+		return fills
+	}
+	row := "mount_span_each_each_page_each_row:"
+	if fills := event("Show"); fills[row+"a"] == 0 {
+		t.Fatalf("the rows were not rendered: %v", fills)
+	}
+
+	// the node of the list survives the render of its component
+	if fills := event("Refresh"); fills["content"] == 0 || fills[row+"a"] != 0 {
+		t.Fatalf("the rows not changed were rendered again: %v", fills)
+	}
+
+	// the render of an ancestor creates the node again, the list is empty
+	if fills := event("Replaced"); fills[row+"a"] == 0 || fills[row+"b"] == 0 {
+		t.Fatalf("the rows were not rendered in the new list: %v", fills)
+	}
+}
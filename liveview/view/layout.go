@@ -2,8 +2,11 @@ package view
 
 import (
 	"fmt"
+	"log"
+	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/html"
@@ -18,7 +21,18 @@ type Layout struct {
 	HandlerEventDestroy    func(id string)
 	HandlerInternalDestroy func()
 	HandlerFirstTime       func()
-	IntervalEventTime      time.Duration
+	// HandlerParams receive the query of the url when the layout is mounted and after every PushPatch
+	HandlerParams     func(params url.Values)
+	IntervalEventTime time.Duration
+
+	muTimers      sync.Mutex
+	timers        map[string]*layoutTimer
+	timersStopped bool
+
+	hub atomic.Pointer[Hub]
+
+	loader *TemplateLoader
+	file   string
 }
 
 func (t *Layout) GetDriver() LiveDriver {
@@ -31,58 +45,29 @@ var (
 	Layaouts map[string]*Layout = make(map[string]*Layout)
 )
 
+// DeleteLayout remove the layout from the hub of DefaultApp
 func DeleteLayout(uid string) {
-	MuLayout.Lock()
-	defer MuLayout.Unlock()
-	if _, ok := Layaouts[uid]; ok {
-		delete(Layaouts, uid)
-		fmt.Println("Layout eliminado:", uid)
-	}
+	DefaultApp.Hub.Delete(uid)
 }
 
+// SendToAllLayouts deliver msg to every layout of DefaultApp
 func SendToAllLayouts(msg interface{}) {
-	MuLayout.RLock() // Lectura concurrente segura
-	layoutsCopy := make([]*Layout, 0, len(Layaouts))
-	for _, v := range Layaouts {
-		layoutsCopy = append(layoutsCopy, v)
-	}
-	MuLayout.RUnlock() // Liberar el bloqueo antes de operar
-
-	for _, v := range layoutsCopy {
-
-		v.HandlerEventIn(msg)
-
-	}
+	DefaultApp.Hub.SendToAll(msg)
 }
-func SendToLayouts(msg interface{}, uuids ...string) {
-	layoutsCopy := make([]*Layout, 0, len(uuids))
-	func() {
-		MuLayout.Lock()
-		defer MuLayout.Unlock()
-		for _, uid := range uuids {
-			if v, ok := Layaouts[uid]; ok {
-				layoutsCopy = append(layoutsCopy, v)
-			}
-		}
-	}()
 
-	for _, v := range layoutsCopy {
-		v.HandlerEventIn(msg)
-	}
+// SendToLayouts deliver msg to the layouts of DefaultApp with uuids
+func SendToLayouts(msg interface{}, uuids ...string) {
+	DefaultApp.Hub.SendTo(msg, uuids...)
 }
 
+// NewLayout create the layout uid of a session. Each call creates a new layout, the
+// sessions do not share them, so uid has to be unique to receive the messages of the hub.
+// Breaking change: it used to return the layout already created with uid, the code that
+// shared a layout with a fixed uid has to use a unique one and broadcast with the Hub
 func NewLayout(uid string, paramHtml string) *ComponentDriver[*Layout] {
 	quit := make(chan struct{})
-	// Verificar si el layout ya existe
-	MuLayout.RLock()
-	if existingLayout, exists := Layaouts[uid]; exists {
-		MuLayout.RUnlock()
-		fmt.Println("Layout ya existe:", uid)
-		return existingLayout.ComponentDriver
-	}
-	MuLayout.RUnlock()
+	var quitOnce sync.Once
 
-	// Si no existe, crear un nuevo layout
 	if Exists(paramHtml) {
 		paramHtml, _ = FileToString(paramHtml)
 	}
@@ -91,24 +76,20 @@ func NewLayout(uid string, paramHtml string) *ComponentDriver[*Layout] {
 		UUID:              uid,
 		Html:              paramHtml,
 		IntervalEventTime: time.Hour * 24,
-		HandlerFirstTime: func() {
-			SendToLayouts("FIRST_TIME", uid)
-		},
 		HandlerEventIn: func(data interface{}) {
 
 		},
 		HandlerEventDestroy: func(id string) {
 
 		},
-		HandlerInternalDestroy: func() {
-			close(quit)
-		},
 	}
-
-	// Guardar el nuevo layout en el mapa de layouts
-	MuLayout.Lock()
-	Layaouts[uid] = c
-	MuLayout.Unlock()
+	c.HandlerFirstTime = func() {
+		c.Hub().SendTo("FIRST_TIME", uid)
+	}
+	c.HandlerInternalDestroy = func() {
+		c.stopTimers()
+		quitOnce.Do(func() { close(quit) })
+	}
 
 	fmt.Println("NewLayout", uid)
 	c.ComponentDriver = NewDriver(uid, c)
@@ -117,12 +98,7 @@ func NewLayout(uid string, paramHtml string) *ComponentDriver[*Layout] {
 	go func() {
 		firstTime := true
 		tickerFirstTime := time.NewTicker(250 * time.Millisecond)
-		tickerEventTime := time.NewTicker(c.IntervalEventTime)
-
-		defer func() {
-			tickerFirstTime.Stop()
-			tickerEventTime.Stop()
-		}()
+		defer tickerFirstTime.Stop()
 
 		for {
 			select {
@@ -134,12 +110,9 @@ func NewLayout(uid string, paramHtml string) *ComponentDriver[*Layout] {
 					if c.HandlerFirstTime != nil {
 						c.HandlerFirstTime()
 					} else {
-						SendToAllLayouts("FIRST_TIME")
+						c.Hub().SendToAll("FIRST_TIME")
 					}
 				}
-			case <-tickerEventTime.C:
-				c.HandlerEventTime()
-
 			}
 		}
 	}()
@@ -169,16 +142,46 @@ func NewLayout(uid string, paramHtml string) *ComponentDriver[*Layout] {
 	return c.ComponentDriver
 }
 
+// Hub return the hub where the layout is registered when its page is mounted
+func (t *Layout) Hub() *Hub {
+	if h := t.hub.Load(); h != nil {
+		return h
+	}
+	return DefaultApp.Hub
+}
+
+// NewLayoutFile create a layout with the template name of loader, when the loader watches
+// its files the layout is rendered again after each change
+func NewLayoutFile(uid string, loader *TemplateLoader, name string) *ComponentDriver[*Layout] {
+	content, err := loader.Load(name)
+	if err != nil {
+		log.Println("NewLayoutFile:", err)
+	}
+	driver := NewLayout(uid, content)
+	driver.Component.loader = loader
+	driver.Component.file = name
+	return driver
+}
+
 func (t *Layout) SetHandlerFirstTime(fx func()) {
 	t.HandlerFirstTime = fx
 }
+func (t *Layout) SetHandlerParams(fx func(params url.Values)) {
+	t.HandlerParams = fx
+}
 func (t *Layout) SetHandlerEventIn(fx func(data interface{})) {
 	t.HandlerEventIn = fx
 }
 
+// SetHandlerEventTime run fx every IntervalEventTime, it is the timer "event_time" of the layout
 func (t *Layout) SetHandlerEventTime(IntervalEventTime time.Duration, fx func()) {
 	t.IntervalEventTime = IntervalEventTime
 	t.HandlerEventTime = fx
+	t.AddTimer("event_time", IntervalEventTime, func() {
+		if t.HandlerEventTime != nil {
+			t.HandlerEventTime()
+		}
+	})
 }
 
 func (t *Layout) SetHandlerEventDestroy(fx func(id string)) {
@@ -189,5 +192,8 @@ func (t *Layout) Start() {
 }
 
 func (t *Layout) GetTemplate() string {
+	if t.loader != nil {
+		return t.loader.Template(t.file)
+	}
 	return t.Html
 }
@@ -0,0 +1,161 @@
+package view
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Mounter is implemented by components that want to know when they are mounted,
+// OnMount is invoked after Start once the component is reachable by events
+type Mounter interface {
+	OnMount()
+}
+
+// Unmounter is implemented by components that want to know when they are unmounted,
+// OnUnmount is invoked after its children were unmounted and its context was cancelled
+type Unmounter interface {
+	OnUnmount()
+}
+
+// Context return the context of the component, it is cancelled when the component
+// or one of its ancestors is unmounted
+func (cw *ComponentDriver[T]) Context() context.Context {
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	if cw.ctx == nil {
+		return context.Background()
+	}
+	return cw.ctx
+}
+
+// Do invoke fx holding the lock of the state of the component, the one of the event
+// handlers, the lv-model changes and Every. The goroutines of Go use it to change the
+// fields and Commit. It must not be called from a handler, it already holds the lock
+func (cw *ComponentDriver[T]) Do(fx func()) {
+	cw.muState.Lock()
+	defer cw.muState.Unlock()
+	fx()
+}
+
+// Go run fx in a goroutine with the context of the component, fx has to return when
+// the context is done
+func (cw *ComponentDriver[T]) Go(fx func(ctx context.Context)) {
+	ctx := cw.Context()
+	go func() {
+		defer HandleRecover()
+		fx(ctx)
+	}()
+}
+
+// Every invoke fx each interval until the component is unmounted or the socket is closed
+func (cw *ComponentDriver[T]) Every(interval time.Duration, fx func()) {
+	cw.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				func() {
+					defer HandleRecover()
+					cw.Do(fx)
+				}()
+			}
+		}
+	})
+}
+
+// SetParent set the driver that mounted this driver
+func (cw *ComponentDriver[T]) SetParent(parent LiveDriver) {
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	cw.parent = parent
+}
+
+// GetParent return the driver that mounted this driver, nil for the root
+func (cw *ComponentDriver[T]) GetParent() LiveDriver {
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	return cw.parent
+}
+
+// IsMounted return true between StartDriver and Destroy
+func (cw *ComponentDriver[T]) IsMounted() bool {
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	return cw.mounted
+}
+
+// Unmount destroy the child mounted with id and remove its node from the page
+func (cw *ComponentDriver[T]) Unmount(id string) {
+	cw.muTree.Lock()
+	key := "mount_span_" + id
+	child, ok := cw.componentsDrivers[key]
+	if !ok {
+		key = id
+		child, ok = cw.componentsDrivers[key]
+	}
+	if ok {
+		delete(cw.componentsDrivers, key)
+	}
+	cw.muTree.Unlock()
+	if !ok {
+		return
+	}
+	child.Destroy()
+	if cw.Conn != nil {
+		cw.Remove(child.GetID())
+	}
+}
+
+// Destroy unmount the component and all its descendants: the children are destroyed
+// first, then the context is cancelled and OnUnmount invoked
+func (cw *ComponentDriver[T]) Destroy() {
+	cw.muTree.Lock()
+	if !cw.started {
+		cw.muTree.Unlock()
+		return
+	}
+	cw.mounted = false
+	cw.started = false
+	children := cw.children()
+	cancel := cw.cancel
+	cw.muTree.Unlock()
+
+	for _, c := range children {
+		c.Destroy()
+	}
+	if cancel != nil {
+		cancel()
+	}
+	if u, ok := any(cw.Component).(Unmounter); ok {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Println("Recovered in OnUnmount:", r)
+				}
+			}()
+			u.OnUnmount()
+		}()
+	}
+	// the temp files of the uploads not moved by OnUnmount are deleted
+	cw.clearAllUploads()
+	if cw.DriversPage != nil {
+		cw.muDrivers.Lock()
+		if d, ok := (*cw.DriversPage)[cw.GetIDComponet()]; ok && d == LiveDriver(cw) {
+			delete(*cw.DriversPage, cw.GetIDComponet())
+		}
+		cw.muDrivers.Unlock()
+	}
+}
+
+// children return a copy of the mounted children, the caller must hold muTree
+func (cw *ComponentDriver[T]) children() []LiveDriver {
+	children := make([]LiveDriver, 0, len(cw.componentsDrivers))
+	for _, c := range cw.componentsDrivers {
+		children = append(children, c)
+	}
+	return children
+}
@@ -0,0 +1,47 @@
+package view
+
+import (
+	"testing"
+)
+
+func TestStartDriverTwice(t *testing.T) {
+	drivers := make(map[string]LiveDriver)
+	channels := make(map[string]chan interface{})
+	d := NewDriver("twice", &fuzzForm{})
+	d.StartDriver(nil, &drivers, &channels)
+	ctx := d.Context()
+	d.StartDriver(nil, &drivers, &channels)
+	if d.Context() != ctx {
+		t.Fatal("the second StartDriver replaced the context of the first one")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("the context was cancelled by the second StartDriver")
+	}
+	d.Destroy()
+	if ctx.Err() == nil {
+		t.Fatal("Destroy did not cancel the context")
+	}
+	if _, ok := drivers["twice"]; ok {
+		t.Fatal("Destroy did not remove the driver of the session")
+	}
+	d.Destroy()
+}
+
+func TestNewLayoutPerSession(t *testing.T) {
+	a := NewLayout("layout_test", `<div></div>`)
+	b := NewLayout("layout_test", `<div></div>`)
+	if a == b {
+		t.Fatal("NewLayout returned the layout of another session")
+	}
+	hub := NewHub()
+	hub.Add(a.Component)
+	hub.Add(b.Component)
+	hub.remove(a.Component)
+	if l, ok := hub.Get("layout_test"); !ok || l != b.Component {
+		t.Fatal("removing the first layout removed the second one from the hub")
+	}
+	for _, d := range []*ComponentDriver[*Layout]{a, b} {
+		d.Component.HandlerInternalDestroy()
+		d.Component.HandlerInternalDestroy()
+	}
+}
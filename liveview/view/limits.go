@@ -0,0 +1,243 @@
+package view
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+)
+
+// DefaultMaxMessageSize is the size limit of a message of the client when
+// Limits.MaxMessageSize is 0, it has room for the pieces of the uploads
+const DefaultMaxMessageSize int64 = 256 << 10
+
+// DefaultMaxProtocolErrors is the number of messages not valid answered with an error
+// when Limits.MaxProtocolErrors is 0
+const DefaultMaxProtocolErrors = 32
+
+// ViolationPolicy is what the session does with a message over the limits
+type ViolationPolicy int
+
+const (
+	// PolicyDrop ignore the message
+	PolicyDrop ViolationPolicy = iota
+	// PolicyWarn ignore the message and send an error to the client
+	PolicyWarn
+	// PolicyDisconnect close the connection
+	PolicyDisconnect
+)
+
+// the kinds of the violations
+const (
+	ViolationSize        = "size"
+	ViolationRate        = "rate"
+	ViolationConcurrency = "concurrency"
+	ViolationProtocol    = "protocol"
+)
+
+// Limits are the limits of the messages of each connection of a page
+type Limits struct {
+	// MaxMessageSize is the size limit of a message in bytes, DefaultMaxMessageSize when
+	// it is 0. The rest of a bigger message can not be read, so the connection is always
+	// closed, whatever the Policy
+	MaxMessageSize int64
+	// EventRate is the number of events per second allowed, without limit when it is 0.
+	// The pieces of the uploads, the changes of lv-model and the replies to the server are
+	// not events, the messages that can not be decoded are
+	EventRate float64
+	// EventBurst is the number of events allowed at once, the EventRate rounded up when it is 0
+	EventBurst int
+	// MaxConcurrent is the number of event handlers running at the same time, without
+	// limit when it is 0
+	MaxConcurrent int
+	// MaxProtocolErrors is the number of messages not valid answered with an error,
+	// DefaultMaxProtocolErrors when it is 0. The next ones are protocol violations
+	MaxProtocolErrors int
+	// Policy is applied to the events over EventRate or MaxConcurrent and to the
+	// messages over MaxProtocolErrors
+	Policy ViolationPolicy
+	// OnViolation is invoked with each violation, before the policy is applied
+	OnViolation func(s *Session, v Violation)
+}
+
+// Violation is a message of the client over the limits
+type Violation struct {
+	// Kind is ViolationSize, ViolationRate, ViolationConcurrency or ViolationProtocol
+	Kind string
+	// Type is the type of the message, empty when it could not be read
+	Type   string
+	Policy ViolationPolicy
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("liveview: %s limit exceeded by %q message", v.Kind, v.Type)
+}
+
+// tokenBucket allow rate events per second with bursts of burst events
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// allow take a token when there is one
+func (b *tokenBucket) allow(now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// countsAsEvent return true for the messages limited by EventRate
+func countsAsEvent(mtype string) bool {
+	switch mtype {
+	case "upload_chunk", "get", "model":
+		return false
+	}
+	return true
+}
+
+// applyLimits prepare the rate and the concurrency limits of the session, the read loop
+// set the read limit of the connection before the next message. The limits change when
+// the session navigates to another page
+func (s *Session) applyLimits(limits Limits) {
+	if limits.MaxMessageSize <= 0 {
+		limits.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if limits.MaxProtocolErrors <= 0 {
+		limits.MaxProtocolErrors = DefaultMaxProtocolErrors
+	}
+	var handlers chan struct{}
+	if limits.MaxConcurrent > 0 {
+		handlers = make(chan struct{}, limits.MaxConcurrent)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+	s.bucket = newTokenBucket(limits.EventRate, limits.EventBurst)
+	s.handlers = handlers
+}
+
+// currentLimits return the limits of the page mounted, its token bucket and the slots of
+// its handlers
+func (s *Session) currentLimits() (Limits, *tokenBucket, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limits, s.bucket, s.handlers
+}
+
+// allowEvent apply EventRate to a message, false when it must be ignored
+func (s *Session) allowEvent(mtype string) bool {
+	_, bucket, _ := s.currentLimits()
+	if !countsAsEvent(mtype) || bucket.allow(time.Now()) {
+		return true
+	}
+	s.violation(ViolationRate, mtype)
+	return false
+}
+
+// protocolError answer a message not valid with its error, the messages over
+// MaxProtocolErrors are violations and are not answered
+func (s *Session) protocolError(perr *ProtocolError) {
+	limits, _, _ := s.currentLimits()
+	s.protocolErrors++
+	if s.protocolErrors > limits.MaxProtocolErrors {
+		s.violation(ViolationProtocol, "")
+		return
+	}
+	log.Println("liveview:", perr)
+	s.sendError(perr.Code, perr.Message)
+}
+
+// violation report the violation and apply the policy, the size violations always disconnect
+func (s *Session) violation(kind string, mtype string) {
+	limits, _, _ := s.currentLimits()
+	v := Violation{Kind: kind, Type: mtype, Policy: limits.Policy}
+	if kind == ViolationSize {
+		v.Policy = PolicyDisconnect
+	}
+	// s.page changes with the navigation, the app of the session is set once
+	if s.app != nil {
+		s.app.Violations.Add(kind)
+	}
+	if limits.OnViolation != nil {
+		func() {
+			defer HandleRecover()
+			limits.OnViolation(s, v)
+		}()
+	}
+	switch v.Policy {
+	case PolicyWarn:
+		s.sendError(kind+"_limit", v.Error())
+	case PolicyDisconnect:
+		log.Println(v.Error())
+		s.disconnect(websocket.ClosePolicyViolation, kind+" limit exceeded")
+	}
+}
+
+// disconnect close the connection with code, the read loop ends
+func (s *Session) disconnect(code int, text string) {
+	if s.Conn == nil {
+		return
+	}
+	muws.Lock()
+	defer muws.Unlock()
+	s.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(time.Second))
+	s.Conn.Close()
+}
+
+// sendError send {"type":"error","code":code,"value":message} to the client
+func (s *Session) sendError(code string, message string) {
+	s.sendNow(map[string]interface{}{"type": "error", "code": code, "value": message})
+}
+
+// eventRunner run an event and return when its handler is done
+type eventRunner interface {
+	runEvent(name string, data interface{})
+}
+
+// dispatch execute the event on d, within MaxConcurrent handlers
+func (s *Session) dispatch(d LiveDriver, event string, data interface{}) {
+	_, _, handlers := s.currentLimits()
+	r, ok := d.(eventRunner)
+	if !ok || handlers == nil {
+		d.ExecuteEvent(event, data)
+		return
+	}
+	select {
+	case handlers <- struct{}{}:
+	default:
+		s.violation(ViolationConcurrency, "data")
+		return
+	}
+	go func() {
+		defer func() { <-handlers }()
+		defer HandleRecover()
+		r.runEvent(event, data)
+	}()
+}
@@ -0,0 +1,152 @@
+package view
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	fasthttpws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// dialPage serve pc in a new app and open its websocket, without fx the page is an
+// empty layout
+func dialPage(t *testing.T, pc *PageControl, fx ...func() LiveDriver) (*App, *fasthttpws.Conn) {
+	t.Helper()
+	return dialPageWith(t, pc, nil, fx...)
+}
+
+// dialPageWith is dialPage sending header in the handshake, for example the cookies
+func dialPageWith(t *testing.T, pc *PageControl, header http.Header, fx ...func() LiveDriver) (*App, *fasthttpws.Conn) {
+	t.Helper()
+	router := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app := NewApp(router)
+	pc.Path = "/"
+	pc.DisableCSRF = true
+	page := func() LiveDriver {
+		return NewLayout("limits_"+uuid.NewString(), `<div></div>`)
+	}
+	if len(fx) > 0 {
+		page = fx[0]
+	}
+	app.Page(pc, page)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go router.Listener(ln)
+	t.Cleanup(func() { router.Shutdown() })
+	conn, _, err := fasthttpws.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws_goliveview", header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return app, conn
+}
+
+// readMessages return a function that return the messages received until the connection
+// is quiet, with the frames flattened. A read that times out breaks the connection, so
+// the messages are read by a goroutine
+func readMessages(conn *fasthttpws.Conn) func() []map[string]interface{} {
+	messages := make(chan map[string]interface{}, 64)
+	go func() {
+		defer close(messages)
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if frame, ok := msg["messages"].([]interface{}); ok {
+				for _, m := range frame {
+					messages <- m.(map[string]interface{})
+				}
+				continue
+			}
+			messages <- msg
+		}
+	}()
+	return func() []map[string]interface{} {
+		out := []map[string]interface{}{}
+		for {
+			select {
+			case msg, ok := <-messages:
+				if !ok {
+					return out
+				}
+				out = append(out, msg)
+			case <-time.After(300 * time.Millisecond):
+				return out
+			}
+		}
+	}
+}
+
+// waitCount wait until the violations of kind are n
+func waitCount(t *testing.T, app *App, kind string, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for app.Violations.Count(kind) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("violations %q = %d, want %d", kind, app.Violations.Count(kind), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	reported := make(chan Violation, 4)
+	app, conn := dialPage(t, &PageControl{Limits: Limits{
+		MaxMessageSize: 64,
+		OnViolation:    func(s *Session, v Violation) { reported <- v },
+	}})
+	if err := conn.WriteMessage(fasthttpws.TextMessage, bytes.Repeat([]byte("a"), 1024)); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	waitCount(t, app, ViolationSize, 1)
+	if v := <-reported; v.Kind != ViolationSize || v.Policy != PolicyDisconnect {
+		t.Fatalf("OnViolation got %+v", v)
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	app, conn := dialPage(t, &PageControl{Limits: Limits{MaxProtocolErrors: 2}})
+	for i := 0; i < 5; i++ {
+		if err := conn.WriteMessage(fasthttpws.TextMessage, []byte("garbage")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitCount(t, app, ViolationProtocol, 3)
+	errorsRead := 0
+	conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	for {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		if msg["type"] == "error" {
+			errorsRead++
+		}
+	}
+	if errorsRead != 2 {
+		t.Fatalf("%d errors sent, want 2", errorsRead)
+	}
+}
+
+func TestRateChargesInvalidMessages(t *testing.T) {
+	app, conn := dialPage(t, &PageControl{Limits: Limits{EventRate: 1, EventBurst: 1}})
+	for i := 0; i < 3; i++ {
+		if err := conn.WriteMessage(fasthttpws.TextMessage, []byte("garbage")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitCount(t, app, ViolationRate, 2)
+}
@@ -0,0 +1,154 @@
+package view
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// TemplateLoader read the templates of components and layouts from a fs.FS. The files are
+// read once, unless the loader watches them with Watch (development mode): then the
+// changed files are read again and every live component using them is committed
+type TemplateLoader struct {
+	FS fs.FS
+
+	mu       sync.RWMutex
+	cache    map[string]string
+	modTimes map[string]time.Time
+	stop     chan struct{}
+}
+
+// NewTemplateLoader create a loader that reads from fsys, for example an embed.FS
+func NewTemplateLoader(fsys fs.FS) *TemplateLoader {
+	return &TemplateLoader{
+		FS:       fsys,
+		cache:    make(map[string]string),
+		modTimes: make(map[string]time.Time),
+	}
+}
+
+// NewDirTemplateLoader create a loader that reads the files of dir
+func NewDirTemplateLoader(dir string) *TemplateLoader {
+	return NewTemplateLoader(os.DirFS(dir))
+}
+
+// Load return the content of the template name
+func (l *TemplateLoader) Load(name string) (string, error) {
+	l.mu.RLock()
+	content, ok := l.cache[name]
+	l.mu.RUnlock()
+	if ok {
+		return content, nil
+	}
+	return l.read(name)
+}
+
+// Template return the content of the template name, the errors are logged and return an
+// empty template. It is useful inside GetTemplate
+func (l *TemplateLoader) Template(name string) string {
+	content, err := l.Load(name)
+	if err != nil {
+		log.Println("TemplateLoader:", err)
+	}
+	return content
+}
+
+func (l *TemplateLoader) read(name string) (string, error) {
+	b, err := fs.ReadFile(l.FS, name)
+	if err != nil {
+		return "", err
+	}
+	var modTime time.Time
+	if info, err := fs.Stat(l.FS, name); err == nil {
+		modTime = info.ModTime()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache[name] = string(b)
+	l.modTimes[name] = modTime
+	return string(b), nil
+}
+
+// Watch check every interval if the templates read changed, the changed templates are read
+// again and the live components rendering them are committed. Use it only in development
+func (l *TemplateLoader) Watch(interval time.Duration) {
+	l.mu.Lock()
+	if l.stop != nil {
+		l.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	l.stop = stop
+	l.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if l.reload() {
+					CommitChangedTemplates()
+				}
+			}
+		}
+	}()
+}
+
+// Close stop watching the templates
+func (l *TemplateLoader) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+}
+
+// reload read again the templates modified, it return true if any changed
+func (l *TemplateLoader) reload() bool {
+	l.mu.RLock()
+	modTimes := make(map[string]time.Time, len(l.modTimes))
+	for name, modTime := range l.modTimes {
+		modTimes[name] = modTime
+	}
+	l.mu.RUnlock()
+
+	changed := false
+	for name, modTime := range modTimes {
+		info, err := fs.Stat(l.FS, name)
+		if err != nil || info.ModTime().Equal(modTime) {
+			continue
+		}
+		if _, err := l.read(name); err != nil {
+			log.Println("TemplateLoader:", err)
+			continue
+		}
+		log.Println("TemplateLoader: reloaded", name)
+		changed = true
+	}
+	return changed
+}
+
+// CommitChangedTemplates commit the live components whose template changed since their
+// last Commit
+func CommitChangedTemplates() {
+	var list []*Session
+	for _, a := range allApps() {
+		list = append(list, a.sessionList()...)
+	}
+	for _, s := range list {
+		for _, d := range s.driverList() {
+			if t, ok := d.(interface{ templateChanged() bool }); ok && t.templateChanged() {
+				go func(d LiveDriver) {
+					defer HandleRecover()
+					d.Commit()
+				}(d)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package view
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// writeTemplate write content in dir/name with a modification time after the previous one
+func writeTemplate(t *testing.T, dir, name, content string, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	writeTemplate(t, dir, "a.html", "v1", start)
+	writeTemplate(t, dir, "b.html", "b1", start)
+	l := NewDirTemplateLoader(dir)
+	if got := l.Template("a.html"); got != "v1" {
+		t.Fatalf("Template = %q, want v1", got)
+	}
+	if got := l.Template("missing.html"); got != "" {
+		t.Errorf("Template of a missing file = %q, want empty", got)
+	}
+
+	// without Watch the files are read once
+	writeTemplate(t, dir, "a.html", "v2", start.Add(time.Minute))
+	if got := l.Template("a.html"); got != "v1" {
+		t.Errorf("Template = %q without Watch, want the cached v1", got)
+	}
+	if !l.reload() {
+		t.Error("reload did not detect the change of a.html")
+	}
+	if l.reload() {
+		t.Error("reload detected a change in files not modified")
+	}
+
+	l.Watch(10 * time.Millisecond)
+	defer l.Close()
+	writeTemplate(t, dir, "a.html", "v3", start.Add(2*time.Minute))
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Template("a.html") != "v3" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Template = %q, want v3 after Watch", l.Template("a.html"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := l.Template("b.html"); got != "b1" {
+		t.Errorf("Template of b.html not modified = %q, want b1", got)
+	}
+}
+
+func TestCommitChangedTemplates(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	writeTemplate(t, dir, "page.html", `<div id="watched">v1</div>`, start)
+	l := NewDirTemplateLoader(dir)
+	uid := "watch_" + uuid.NewString()
+	_, conn := dialPage(t, &PageControl{}, func() LiveDriver {
+		return NewLayoutFile(uid, l, "page.html")
+	})
+	read := readMessages(conn)
+	read()
+
+	CommitChangedTemplates()
+	for _, msg := range read() {
+		t.Errorf("unchanged template committed: %v", msg)
+	}
+
+	writeTemplate(t, dir, "page.html", `<div id="watched">v2</div>`, start.Add(time.Minute))
+	if !l.reload() {
+		t.Fatal("reload did not detect the change")
+	}
+	CommitChangedTemplates()
+	found := false
+	for _, msg := range read() {
+		if strings.Contains(fmt.Sprint(msg["value"]), "v2") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("the layout was not committed with the changed template")
+	}
+}
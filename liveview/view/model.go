@@ -2,20 +2,26 @@ package view
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/gofiber/websocket/v2"
+	"io"
 	"log"
 	"reflect"
 	"sync"
-	"text/template"
 
 	"github.com/google/uuid"
 )
 
 var (
-	componentsDrivers map[string]LiveDriver = make(map[string]LiveDriver)
-	mu                sync.Mutex
-	muws              sync.Mutex = sync.Mutex{}
+	mu   sync.Mutex
+	muws sync.Mutex = sync.Mutex{}
+	// muBuild serialize the functions of the pages, building has the components created
+	// with New by the function running
+	muBuild     sync.Mutex
+	muBuilding  sync.Mutex
+	building    map[string]LiveDriver
+	buildingApp *App
 )
 
 // Component it is interface for implement one component
@@ -60,6 +66,14 @@ type LiveDriver interface {
 	GetElementById(string) string
 
 	SetData(interface{})
+	GetData() interface{}
+
+	Context() context.Context
+	SetParent(LiveDriver)
+	GetParent() LiveDriver
+	IsMounted() bool
+	Unmount(id string)
+	Destroy()
 }
 
 func (cw *ComponentDriver[T]) SetData(data interface{}) {
@@ -78,10 +92,35 @@ type ComponentDriver[T Component] struct {
 	Conn              *websocket.Conn
 	componentsDrivers map[string]LiveDriver
 	DriversPage       *map[string]LiveDriver
-	channelIn         *map[string]chan interface{}
+	// muDrivers guard DriversPage, it is the lock of the drivers of the session
+	muDrivers *sync.Mutex
+	channelIn *map[string]chan interface{}
+	// muChannels guard channelIn, it is the lock of the replies of the session
+	muChannels *sync.Mutex
 	// Events has rewrite of our implementings of  events, examples click, change, keyup, keydown, etc
 	Events map[string]func(c T, data interface{})
 	Data   interface{}
+
+	// muState is held by the event handlers, the lv-model changes and the timers, so they
+	// do not change the fields of the component at the same time, see Do
+	muState sync.Mutex
+	muTree  sync.Mutex
+	parent  LiveDriver
+	started bool
+	mounted bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	lists        map[string]*keyedList
+	eachChildren map[string]string
+	pendingEach  []*eachUpdate
+	// eachCount number the calls to mountEach of each name in the render
+	eachCount map[string]int
+
+	lastTemplate string
+	// models are the fields bound with lv-model in the last render
+	models  map[string]bool
+	uploads uploads
 }
 
 func (cw *ComponentDriver[T]) SetEvent(name string, fx func(c T, data interface{})) {
@@ -94,18 +133,45 @@ func (cw *ComponentDriver[T]) GetIDComponet() string {
 
 // Commit render of component
 func (cw *ComponentDriver[T]) Commit() {
+	cw.commit(false)
+}
+
+// commit render the component, replaced is true when the render of an ancestor created
+// the node of the component again, so its lists are empty in the browser
+func (cw *ComponentDriver[T]) commit(replaced bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Println("Recovered in Commit:", r)
 		}
 	}()
-	t := template.Must(template.New("component").Funcs(FuncMapTemplate).Parse(cw.Component.GetTemplate()))
 	buf := new(bytes.Buffer)
-	err := t.Execute(buf, cw.Component)
-	if err != nil {
-		log.Println(err)
+	if err := cw.render(buf); err != nil {
+		log.Println("Commit", cw.GetIDComponet(), err)
+		return
 	}
+	cw.bindModels(buf.Bytes())
 	cw.FillValueById(cw.GetID(), buf.String())
+	cw.flushEach(replaced)
+	cw.syncQuery()
+
+	// the fill replaced the mount points of the children, render them again
+	cw.muTree.Lock()
+	children := cw.children()
+	cw.muTree.Unlock()
+	for _, c := range children {
+		if c.IsMounted() && !cw.isEachChild(c.GetID()) {
+			recommit(c)
+		}
+	}
+}
+
+// recommit render c after the node of c was replaced
+func recommit(c LiveDriver) {
+	if r, ok := c.(interface{ commit(replaced bool) }); ok {
+		r.commit(true)
+		return
+	}
+	c.Commit()
 }
 
 func (cw *ComponentDriver[T]) StartDriver(ws *websocket.Conn, drivers *map[string]LiveDriver, channelIn *map[string]chan interface{}) {
@@ -114,15 +180,46 @@ func (cw *ComponentDriver[T]) StartDriver(ws *websocket.Conn, drivers *map[strin
 			fmt.Println("Recovered in f", r)
 		}
 	}()
+	parentCtx := context.Background()
+	if parent := cw.GetParent(); parent != nil {
+		parentCtx = parent.Context()
+	}
+	// a driver is started once, a second start would leave the goroutines of the first
+	// one running with a context that is never cancelled
+	cw.muTree.Lock()
+	if cw.started {
+		cw.muTree.Unlock()
+		log.Println("StartDriver:", cw.GetIDComponet(), "is already started")
+		return
+	}
+	cw.started = true
+	if cw.cancel != nil {
+		cw.cancel()
+	}
+	cw.ctx, cw.cancel = context.WithCancel(parentCtx)
+	cw.muTree.Unlock()
 	cw.Conn = ws
-	cw.Component.Start()
+	if s := sessionFor(ws); s != nil {
+		u := s.URL()
+		cw.loadQuery(u.Query(), false)
+	}
 	cw.DriversPage = drivers
+	cw.muDrivers = driversLock(ws)
 	cw.channelIn = channelIn
-	mu.Lock()
+	cw.muChannels = channelsLock(ws)
+	cw.Component.Start()
+	cw.muDrivers.Lock()
 	(*drivers)[cw.GetIDComponet()] = cw
-	mu.Unlock()
+	cw.muDrivers.Unlock()
+	cw.muTree.Lock()
+	cw.mounted = true
+	children := cw.children()
+	cw.muTree.Unlock()
+	if m, ok := any(cw.Component).(Mounter); ok {
+		m.OnMount()
+	}
 	var wg sync.WaitGroup
-	for _, c := range cw.componentsDrivers {
+	for _, c := range children {
 		wg.Add(1)
 		go func(c LiveDriver) {
 			defer HandleRecover()
@@ -133,6 +230,39 @@ func (cw *ComponentDriver[T]) StartDriver(ws *websocket.Conn, drivers *map[strin
 	wg.Wait()
 }
 
+// render write the html of the component, with Render when it is a Renderer or
+// ContextRenderer, else with its template
+func (cw *ComponentDriver[T]) render(w io.Writer) error {
+	switch r := interface{}(cw.Component).(type) {
+	case Renderer:
+		return r.Render(w)
+	case ContextRenderer:
+		return r.Render(cw.Context(), w)
+	}
+	var page *PageControl
+	if s := sessionFor(cw.Conn); s != nil {
+		page = s.Page()
+	}
+	text := cw.Component.GetTemplate()
+	cw.muTree.Lock()
+	cw.lastTemplate = text
+	cw.eachCount = nil
+	cw.muTree.Unlock()
+	t, err := componentTemplate(cw.Component, text, page)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, cw.Component)
+}
+
+// templateChanged return true when GetTemplate does not return the template of the last Commit
+func (cw *ComponentDriver[T]) templateChanged() bool {
+	text := cw.Component.GetTemplate()
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
+	return text != cw.lastTemplate
+}
+
 // GetID return id of driver
 func (cw *ComponentDriver[T]) GetComponet() Component {
 	return cw.Component
@@ -140,6 +270,8 @@ func (cw *ComponentDriver[T]) GetComponet() Component {
 
 // GetID return id of driver
 func (cw *ComponentDriver[T]) GetDriverById(id string) LiveDriver {
+	cw.muTree.Lock()
+	defer cw.muTree.Unlock()
 	if c, ok := cw.componentsDrivers["mount_span_"+id]; ok {
 		return c
 	}
@@ -147,7 +279,7 @@ func (cw *ComponentDriver[T]) GetDriverById(id string) LiveDriver {
 		return c
 	}
 	c := &None{}
-	New(id, c)
+	NewDriver(id, c).SetID("mount_span_" + id)
 	return c
 }
 
@@ -166,15 +298,21 @@ func (cw *ComponentDriver[T]) Mount(component Component) LiveDriver {
 	componentDriver := component.GetDriver()
 	id := "mount_span_" + componentDriver.GetIDComponet()
 	componentDriver.SetID(id)
+	componentDriver.SetParent(cw)
+	cw.muTree.Lock()
 	cw.componentsDrivers[id] = componentDriver
+	cw.muTree.Unlock()
 	return cw
 }
 
 // Mount mount component in other component"mount_span_" +
 func (cw *ComponentDriver[T]) MountWithStart(ws *websocket.Conn, id string, componentDriver LiveDriver) LiveDriver {
 	componentDriver.SetID(id)
+	componentDriver.SetParent(cw)
 	cw.Conn = ws
+	cw.muTree.Lock()
 	cw.componentsDrivers[id] = componentDriver
+	cw.muTree.Unlock()
 	componentDriver.StartDriver(ws, cw.DriversPage, cw.channelIn)
 	return cw
 }
@@ -185,15 +323,49 @@ func Join(ids ...string) {
 	}
 }
 
+// New create the driver of c with the mount point id. Inside the function of
+// PageControl.Register the component is mounted in the layout of the session that called it,
+// outside of it the component is not mounted anywhere and it is logged
 func New[T Component](id string, c T) T {
 	NewDriver(id, c)
 	componentDriver := c.GetDriver()
 	idMount := "mount_span_" + componentDriver.GetIDComponet()
 	componentDriver.SetID(idMount)
-	componentsDrivers[idMount] = componentDriver
+	muBuilding.Lock()
+	defer muBuilding.Unlock()
+	if building == nil {
+		log.Println("New:", id, "is not mounted, create it inside the function of the page")
+		return c
+	}
+	building[idMount] = componentDriver
 	return c
 }
 
+// buildPage call fx and return its layout with the components created by New inside fx,
+// so every session has its own components
+func buildPage(app *App, fx func() LiveDriver) (LiveDriver, []LiveDriver) {
+	muBuild.Lock()
+	defer muBuild.Unlock()
+	muBuilding.Lock()
+	building = make(map[string]LiveDriver)
+	buildingApp = app
+	muBuilding.Unlock()
+	defer func() {
+		muBuilding.Lock()
+		building = nil
+		buildingApp = nil
+		muBuilding.Unlock()
+	}()
+	content := fx()
+	muBuilding.Lock()
+	defer muBuilding.Unlock()
+	children := make([]LiveDriver, 0, len(building))
+	for _, d := range building {
+		children = append(children, d)
+	}
+	return content, children
+}
+
 func NewWithTemplate(id string, template string) *None {
 	return New(id, &None{Template: template})
 }
@@ -217,6 +389,8 @@ func NewDriver[T Component](id string, c T) *ComponentDriver[T] {
 			field.Set(reflect.ValueOf(driver))
 		}
 	}
+	// the template errors are reported when the component is created and not in each Commit
+	logTemplateError(id, c)
 	return driver
 }
 
@@ -234,96 +408,110 @@ func (cw *ComponentDriver[T]) ExecuteEvent(name string, data interface{}) {
 	}
 	go func(cw *ComponentDriver[T]) {
 		defer HandleRecover()
-		if data == nil {
-			data = make(map[string]interface{})
-		}
+		cw.runEvent(name, data)
+	}(cw)
+}
 
-		if cw.Events != nil {
-			if fx, ok := cw.Events[name]; ok {
-				go func() {
-					defer HandleRecover()
-					fx(cw.Component, data)
-				}()
-				return
-			}
-		}
-		func() {
-			defer HandleRecoverPass()
-			in := []reflect.Value{reflect.ValueOf(data)}
-			reflect.ValueOf(cw.Component).MethodByName(name).Call(in)
-		}()
+// hasEvent return true when name is in Events or it is a method of the component with one
+// parameter interface{}. The methods of the driver are not events
+func (cw *ComponentDriver[T]) hasEvent(name string) bool {
+	if _, ok := cw.Events[name]; ok {
+		return true
+	}
+	if _, ok := reflect.TypeOf(cw).MethodByName(name); ok {
+		return false
+	}
+	m := reflect.ValueOf(cw.Component).MethodByName(name)
+	if !m.IsValid() || m.Type().NumIn() != 1 {
+		return false
+	}
+	in := m.Type().In(0)
+	return in.Kind() == reflect.Interface && in.NumMethod() == 0
+}
 
-	}(cw)
+// runEvent is ExecuteEvent in the goroutine of the caller, it returns when the handler is done
+func (cw *ComponentDriver[T]) runEvent(name string, data interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	cw.muState.Lock()
+	defer cw.muState.Unlock()
+	if cw.Events != nil {
+		if fx, ok := cw.Events[name]; ok {
+			func() {
+				defer HandleRecover()
+				fx(cw.Component, data)
+			}()
+			return
+		}
+	}
+	func() {
+		defer HandleRecoverPass()
+		in := []reflect.Value{reflect.ValueOf(data)}
+		reflect.ValueOf(cw.Component).MethodByName(name).Call(in)
+	}()
 }
 
 // Remove
 func (cw *ComponentDriver[T]) Remove(id string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "remove", "id": id})
+	cw.send(map[string]interface{}{"type": "remove", "id": id})
 }
 
 // AddNode add node to id
 func (cw *ComponentDriver[T]) AddNode(id string, value string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "addNode", "id": id, "value": value})
+	cw.send(map[string]interface{}{"type": "addNode", "id": id, "value": value})
 }
 
 // FillValue is same SetHTML
 func (cw *ComponentDriver[T]) FillValueById(id string, value string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "fill", "id": id, "value": value})
+	cw.send(map[string]interface{}{"type": "fill", "id": id, "value": value})
 }
 
 // FillValue is same SetHTML
 func (cw *ComponentDriver[T]) FillValue(value string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "fill", "id": cw.GetIDComponet(), "value": value})
+	cw.send(map[string]interface{}{"type": "fill", "id": cw.GetIDComponet(), "value": value})
 }
 
 // SetHTML is same FillValue :p haha, execute  document.getElementById("$id").innerHTML = $value
 func (cw *ComponentDriver[T]) SetHTML(value string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "fill", "id": cw.GetIDComponet(), "value": value})
+	cw.send(map[string]interface{}{"type": "fill", "id": cw.GetIDComponet(), "value": value})
 }
 
 // SetText execute document.getElementById("$id").innerText = $value
 func (cw *ComponentDriver[T]) SetText(value string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "text", "id": cw.GetIDComponet(), "value": value})
+	cw.send(map[string]interface{}{"type": "text", "id": cw.GetIDComponet(), "value": value})
 }
 
 // SetPropertie execute  document.getElementById("$id")[$propertie] = $value
 func (cw *ComponentDriver[T]) SetPropertie(propertie string, value interface{}) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "propertie", "id": cw.GetIDComponet(), "propertie": propertie, "value": value})
+	cw.send(map[string]interface{}{"type": "propertie", "id": cw.GetIDComponet(), "propertie": propertie, "value": value})
 }
 
 // SetValue execute document.getElementById("$id").value = $value|
 func (cw *ComponentDriver[T]) SetValue(value interface{}) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "set", "id": cw.GetIDComponet(), "value": value})
+	cw.send(map[string]interface{}{"type": "set", "id": cw.GetIDComponet(), "value": value})
 }
 
-// EvalScript execute eval($code);
+// EvalScript execute eval($code); in the page, it does nothing when the page has DisableEval
 func (cw *ComponentDriver[T]) EvalScript(code string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "script", "value": code})
+	if s := sessionFor(cw.Conn); s != nil {
+		if page := s.Page(); page != nil && page.DisableEval {
+			log.Println("EvalScript: eval is disabled in", page.Path)
+			return
+		}
+	}
+	cw.send(map[string]interface{}{"type": "script", "value": code})
+}
+
+// PushEvent send event with payload to the JS hooks (lv-hook) inside the component that
+// registered it with this.handleEvent(event, callback). The payload is sent as JSON
+func (cw *ComponentDriver[T]) PushEvent(event string, payload interface{}) {
+	cw.send(map[string]interface{}{"type": "hook_event", "id": cw.GetID(), "event": event, "value": payload})
 }
 
 // SetStyle execute  document.getElementById("$id").style.cssText = $style
 func (cw *ComponentDriver[T]) SetStyle(style string) {
-	muws.Lock()
-	defer muws.Unlock()
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "style", "id": cw.GetIDComponet(), "value": style})
+	cw.send(map[string]interface{}{"type": "style", "id": cw.GetIDComponet(), "value": style})
 }
 
 // GetElementById same as GetValue
@@ -357,14 +545,50 @@ func (cw *ComponentDriver[T]) GetPropertie(name string) string {
 	return cw.get(cw.GetIDComponet(), "propertie", name)
 }
 
-func (cw *ComponentDriver[T]) get(id string, subType string, value string) string {
+// send queue msg in the render scheduler of the connection, or write it directly when there is none
+func (cw *ComponentDriver[T]) send(msg map[string]interface{}) {
+	if s := schedulerFor(cw.Conn); s != nil {
+		s.Push(msg)
+		return
+	}
+	// a driver not started has no connection, its messages are dropped
+	if cw.Conn == nil {
+		return
+	}
 	muws.Lock()
 	defer muws.Unlock()
+	cw.Conn.WriteJSON(msg)
+}
+
+func (cw *ComponentDriver[T]) get(id string, subType string, value string) string {
+	// the pending frame has to reach the client before it reads the DOM
+	if s := schedulerFor(cw.Conn); s != nil {
+		s.Flush()
+	}
 	uid := uuid.NewString()
-	(*cw.channelIn)[uid] = make(chan interface{})
-	defer delete((*cw.channelIn), uid)
-	cw.Conn.WriteJSON(map[string]interface{}{"type": "get", "id": id, "value": value, "id_ret": uid, "sub_type": subType})
-	data := <-(*cw.channelIn)[uid]
+	// the reply is buffered, the read loop never waits for it to be received
+	ch := make(chan interface{}, 1)
+	cw.muChannels.Lock()
+	(*cw.channelIn)[uid] = ch
+	cw.muChannels.Unlock()
+	defer func() {
+		cw.muChannels.Lock()
+		delete((*cw.channelIn), uid)
+		cw.muChannels.Unlock()
+	}()
+	// muws is not held while waiting, the read loop may have to write before the reply
+	muws.Lock()
+	err := cw.Conn.WriteJSON(map[string]interface{}{"type": "get", "id": id, "value": value, "id_ret": uid, "sub_type": subType})
+	muws.Unlock()
+	if err != nil {
+		return ""
+	}
+	var data interface{}
+	select {
+	case data = <-ch:
+	case <-cw.Context().Done():
+		return ""
+	}
 	if data != nil {
 		return fmt.Sprint(data)
 	}
@@ -2,13 +2,13 @@ package view
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
-	"net/http"
+	"html/template"
+	"log"
 	"sync"
-	"text/template"
+	"time"
 )
 
 type PageControl struct {
@@ -21,74 +21,187 @@ type PageControl struct {
 	AfterCode string
 	Router    fiber.Router
 	Debug     bool
+	// App is the app of the page, DefaultApp when it is registered with Register
+	App *App
+	// MaxFPS is the maximum number of render frames per second sent to each client, 60 by default
+	MaxFPS int
+	// Funcs are template functions available in the page shell and in the components of the page
+	Funcs template.FuncMap
+	// Blocks override the blocks of the page shell: "head", "header", "nav" and "footer"
+	Blocks map[string]string
+	// BaseTemplate replace templateBase, it must have a div with id content and load the
+	// assets wasm_exec.js and liveview.js, with nonce="{{.Nonce}}" when the page has a CSP,
+	// and <meta name="liveview-csrf" content="{{.CSRFToken}}"> unless DisableCSRF is set.
+	// HeadCode and AfterCode are included with {{template "lv_head_code" .}} and
+	// {{template "lv_after_code" .}}
+	BaseTemplate string
+	// DisableEval make EvalScript a no-op and the client refuse to eval code, use the JS
+	// hooks (lv-hook) instead
+	DisableEval bool
+	// CSP is the Content-Security-Policy sent with the page, see NewCSP
+	CSP *CSP
+	// AllowedOrigins are the origins allowed to open the websocket, like
+	// "https://example.com". Only the origin of the page when it is empty, "*" allows any
+	AllowedOrigins []string
+	// DisableCSRF stop requiring the CSRF token of the page on the websocket handshake
+	DisableCSRF bool
+	// CSRFMaxAge is the age limit of the token of a page, DefaultCSRFMaxAge when it is 0
+	CSRFMaxAge time.Duration
+	// OnReject is invoked when a websocket handshake is rejected, reason is RejectOrigin
+	// or RejectCSRF
+	OnReject func(c *fiber.Ctx, reason string, err error)
+	// Limits are the size, rate and concurrency limits of the messages of each connection
+	Limits Limits
+	// LiveSession is the group of the page for the live navigation: the pages of the same
+	// LiveSession are mounted over the websocket without their route, so they must share
+	// its middlewares. The others, and all of them when it is empty, are loaded by the
+	// browser
+	LiveSession string
+
+	fx            func() LiveDriver
+	shellTemplate *template.Template
+	shellErr      error
 }
 
 var (
-	muDriver     sync.Mutex
 	muChannelIn  sync.Mutex
 	templateBase string = `
 <html lang="{{.Lang}}">
 	<head>
 		<title>{{.Title}}</title>
-		{{.HeadCode}}
-		<style>
+		{{template "lv_head_code" .}}
+		{{block "head" .}}{{end}}
+		<style nonce="{{.Nonce}}">
 			{{.Css}}
 		</style>
 		<meta charset="utf-8"/>
-        <script src="assets/wasm_exec.js"></script>
+		{{if .CSRFToken}}<meta name="liveview-csrf" content="{{.CSRFToken}}"/>{{end}}
+		{{if .DisableEval}}<meta name="liveview-eval" content="off"/>{{end}}
+        <script nonce="{{.Nonce}}" src="assets/wasm_exec.js"></script>
 	</head>
     <body>
+		{{block "header" .}}{{end}}
+		{{block "nav" .}}{{end}}
 		<div id="content"> 
 		</div>
-		<script>
-		const go = new Go();
-		WebAssembly.instantiateStreaming(fetch("assets/json.wasm"), go.importObject).then((result) => {
-			go.run(result.instance);
-		});
-		</script>
-		{{.AfterCode}}
+		{{block "footer" .}}{{end}}
+		<script nonce="{{.Nonce}}" src="assets/liveview.js"></script>
+		{{template "lv_after_code" .}}
     </body>
 </html>
 `
 )
 
+// pageShell is the data of templateBase, the code configured by the developer is trusted.
+// HeadCode and AfterCode are rendered as the templates lv_head_code and lv_after_code,
+// their scripts use {{.Nonce}}
+type pageShell struct {
+	*PageControl
+	Css template.CSS
+	// Nonce is the nonce of the request for the script and style tags
+	Nonce string
+	// CSRFToken is the token required to open the websocket of the page
+	CSRFToken string
+}
+
+func (pc *PageControl) shell(nonce string, csrfToken string) pageShell {
+	return pageShell{
+		PageControl: pc,
+		Css:         template.CSS(pc.Css),
+		Nonce:       nonce,
+		CSRFToken:   csrfToken,
+	}
+}
+
+// parseShell parse the base template with the shared templates and the blocks of the page
+func (pc *PageControl) parseShell() (*template.Template, error) {
+	t, err := pc.App.sharedTemplates()
+	if err != nil {
+		return nil, err
+	}
+	base := templateBase
+	if pc.BaseTemplate != "" {
+		base = pc.BaseTemplate
+	}
+	t, err = t.New("page_control").Funcs(pc.App.funcMap()).Funcs(pc.Funcs).Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.New("lv_head_code").Parse(pc.HeadCode); err != nil {
+		return nil, fmt.Errorf("HeadCode: %w", err)
+	}
+	if _, err := t.New("lv_after_code").Parse(pc.AfterCode); err != nil {
+		return nil, fmt.Errorf("AfterCode: %w", err)
+	}
+	for name, text := range pc.Blocks {
+		if _, err := t.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("block %s: %w", name, err)
+		}
+	}
+	return t, nil
+}
+
+// liveWith return true when the live navigation can go from pc to page
+func (pc *PageControl) liveWith(page *PageControl) bool {
+	return pc == page || (pc.LiveSession != "" && pc.LiveSession == page.LiveSession)
+}
+
 // Register this method to register in router of Echo page and websocket
 func (pc *PageControl) Register(fx func() LiveDriver) {
+	if pc.App == nil {
+		pc.App = DefaultApp
+	}
+	pc.fx = fx
+	pc.App.addPage(pc)
 	if Exists(pc.AfterCode) {
 		pc.AfterCode, _ = FileToString(pc.AfterCode)
 	}
 	if Exists(pc.HeadCode) {
 		pc.HeadCode, _ = FileToString(pc.HeadCode)
 	}
+	if pc.Lang == "" {
+		pc.Lang = pc.App.Lang
+	}
 	if pc.Lang == "" {
 		pc.Lang = "en"
 	}
+	if pc.MaxFPS <= 0 {
+		pc.MaxFPS = pc.App.MaxFPS
+	}
+	if pc.MaxFPS <= 0 {
+		pc.MaxFPS = DefaultMaxFPS
+	}
 	if Exists("live.js") {
 		pc.LiveJs, _ = FileToString("live.js")
 	}
 
-	pc.Router.Get("/assets/:file", func(c *fiber.Ctx) error {
-		file := "../../liveview/assets/" + c.Params("file")
-
-		if Exists(file) {
-			if c.Params("file") == "json.wasm" {
-				c.Set("Content-Type", "application/wasm")
-			}
-			if c.Params("file") == "wasm_exec.js" {
-				c.Set("Content-Type", "application/javascript")
-			}
+	pc.App.registerAssets(pc.Router)
 
-			content, _ := FileToString(file)
-			return c.SendString(content)
-		}
-		return c.SendStatus(http.StatusNotFound)
-	})
+	// el shell se parsea una sola vez
+	pc.shellTemplate, pc.shellErr = pc.parseShell()
+	if pc.shellErr != nil {
+		log.Println("PageControl", pc.Path, pc.shellErr)
+	}
 
 	pc.Router.Get(pc.Path, func(c *fiber.Ctx) error {
-		t := template.Must(template.New("page_control").Parse(templateBase))
+		if pc.shellErr != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString(pc.shellErr.Error())
+		}
+		nonce := newNonce()
+		// la cookie del navegador identifica tambien a quien puede bajar las descargas
+		browser := browserID(c)
+		csrfToken := ""
+		if !pc.DisableCSRF {
+			csrfToken = pc.csrfToken(browser, time.Now())
+		}
 		buf := new(bytes.Buffer)
-		_ = t.Execute(buf, pc)
+		if err := pc.shellTemplate.Execute(buf, pc.shell(nonce, csrfToken)); err != nil {
+			fmt.Println(err)
+		}
 		c.Set("Content-Type", "text/html; charset=utf-8")
+		if pc.CSP != nil {
+			c.Set(pc.CSP.Header(), pc.CSP.String(nonce))
+		}
 		e := c.SendString(buf.String())
 		if e != nil {
 			fmt.Println(e)
@@ -96,89 +209,26 @@ func (pc *PageControl) Register(fx func() LiveDriver) {
 		return nil
 	})
 
-	pc.Router.Get(pc.Path+"ws_goliveview", websocket.New(func(conn *websocket.Conn) {
+	pc.Router.Get(pc.Path+"lv_download/:token", serveDownload)
 
-		content := fx()
+	pc.Router.Get(pc.Path+"ws_goliveview", func(c *fiber.Ctx) error {
+		// se rechazan los origenes no permitidos y los handshakes sin el token de la pagina
+		if !pc.checkHandshake(c) {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		// la query de la pagina llega en la url del websocket, sin el token
+		c.Locals("liveview_query", withoutParam(string(c.Request().URI().QueryString()), csrfParam))
+		c.Locals("liveview_browser", c.Cookies(csrfCookie))
+		return c.Next()
+	}, websocket.New(func(conn *websocket.Conn) {
+		rawQuery, _ := conn.Locals("liveview_query").(string)
+		browser, _ := conn.Locals("liveview_browser").(string)
+		session := newSession(conn, pc, rawQuery, browser)
 
 		// Cleanup y lógica de cierre
-		defer func() {
-			// Eliminar el layout del mapa global
-			func() {
-				id := content.GetIDComponet()
-				DeleteLayout(id)
-			}()
-
-			// Ejecutar el handler de destrucción si existe
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						fmt.Println("Layout has not HandlerEventDestroy method defined", r)
-					}
-				}()
-
-				//Destroy component
-				(content.GetComponet().(*Layout)).HandlerEventDestroy(content.GetIDComponet())
-				(content.GetComponet().(*Layout)).HandlerInternalDestroy()
-			}()
-
-			fmt.Println("Delete Layout:", content.GetIDComponet())
-		}()
-
-		// Montar componentes
-		for _, v := range componentsDrivers {
-			content.Mount(v.GetComponet())
-		}
-		content.SetID("content")
-
-		// Canales
-
-		drivers := make(map[string]LiveDriver)
-		channelIn := make(map[string](chan interface{}))
-
-		// Iniciar driver en goroutine
-		go func() {
-			defer HandleRecover()
-			muChannelIn.Lock()
-			defer muChannelIn.Unlock()
-			muDriver.Lock()
-			defer muDriver.Unlock()
-			content.StartDriver(conn, &drivers, &channelIn)
-		}()
-
-		// Leer mensajes del cliente
-		for {
-			_, msg, err := conn.ReadMessage()
-			if err != nil {
-				fmt.Println("Error leyendo mensaje:", err)
-				break
-			}
-
-			var data map[string]interface{}
-			if err := json.Unmarshal(msg, &data); err != nil {
-				fmt.Println("Error al deserializar JSON:", err)
-				continue
-			}
-
-			// Procesar mensajes
-			if mtype, ok := data["type"]; ok {
-				param := data["data"]
-				if mtype == "data" {
-					func() {
-						muDriver.Lock()
-						defer muDriver.Unlock()
-						drivers[data["id"].(string)].ExecuteEvent(data["event"].(string), param)
-					}()
-				}
-				if mtype == "get" {
-					func() {
-						muChannelIn.Lock()
-						defer muChannelIn.Unlock()
-						channelIn[data["id_ret"].(string)] <- param
-					}()
-
-				}
-			}
-		}
+		defer session.close()
 
+		session.mount(pc)
+		session.read()
 	}))
 }
@@ -0,0 +1,37 @@
+package view
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestShellCode(t *testing.T) {
+	pc := &PageControl{
+		App:       newApp(fiber.New(), NewHub()),
+		Title:     "home",
+		HeadCode:  `<script>var title = "{{.Title}}";</script>`,
+		AfterCode: `<p>{{.Lang}}</p>`,
+		Lang:      "es",
+	}
+	shell, err := pc.parseShell()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := shell.Execute(buf, pc.shell("", "")); err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{`var title = "home";`, `<p>es</p>`} {
+		if !strings.Contains(buf.String(), code) {
+			t.Fatalf("the shell does not include %s:\n%s", code, buf)
+		}
+	}
+
+	pc.AfterCode = `<p>{{</p>`
+	if _, err := pc.parseShell(); err == nil || !strings.HasPrefix(err.Error(), "AfterCode:") {
+		t.Fatalf("parseShell error %v, want the error of AfterCode", err)
+	}
+}
@@ -0,0 +1,64 @@
+package view
+
+import (
+	"html/template"
+	"io/fs"
+	"path"
+)
+
+// DefineTemplate add a named template to the set shared by every component and page shell
+// of DefaultApp, it is used with {{template "name" .}}. Text can also have its own {{define}}
+// blocks. Define the templates before registering the pages
+func DefineTemplate(name string, text string) error {
+	return DefaultApp.DefineTemplate(name, text)
+}
+
+// DefineTemplate add a named template to the set shared by the components and page shells
+// of the app
+func (a *App) DefineTemplate(name string, text string) error {
+	funcs := a.pageFuncs()
+	for fname, fn := range a.funcMap() {
+		funcs[fname] = fn
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.partials.New(name).Funcs(funcs).Parse(text); err != nil {
+		return err
+	}
+	clearTemplates(a)
+	return nil
+}
+
+// DefinePartials add the files of the loader matching the patterns to the shared templates
+// of DefaultApp, each one named as its file, for example {{template "header.html" .}}
+func (l *TemplateLoader) DefinePartials(patterns ...string) error {
+	return DefaultApp.DefinePartials(l, patterns...)
+}
+
+// DefinePartials add the files of l matching the patterns to the shared templates of the app
+func (a *App) DefinePartials(l *TemplateLoader, patterns ...string) error {
+	for _, pattern := range patterns {
+		names, err := fs.Glob(l.FS, pattern)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			content, err := l.Load(name)
+			if err != nil {
+				return err
+			}
+			if err := a.DefineTemplate(path.Base(name), content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sharedTemplates return a copy of the shared templates of the app where a template can
+// be parsed
+func (a *App) sharedTemplates() (*template.Template, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.partials.Clone()
+}
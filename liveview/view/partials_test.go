@@ -0,0 +1,122 @@
+package view
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type partialUser struct {
+	*ComponentDriver[*partialUser]
+	Name string
+}
+
+func (c *partialUser) GetDriver() LiveDriver { return c }
+func (c *partialUser) Start()                {}
+func (c *partialUser) GetTemplate() string {
+	return `<div>{{template "badge.html" .Name}}</div>`
+}
+
+func renderShell(t *testing.T, pc *PageControl) string {
+	t.Helper()
+	shell, err := pc.parseShell()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := shell.Execute(buf, pc.shell("", "")); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestPartials(t *testing.T) {
+	app := NewApp(nil)
+	l := NewTemplateLoader(fstest.MapFS{
+		"partials/badge.html": {Data: []byte(`<b>{{.}}</b>`)},
+		"partials/menu.html":  {Data: []byte(`<nav>menu</nav>`)},
+		"other.txt":           {Data: []byte(`not a partial`)},
+	})
+	if err := app.DefinePartials(l, "partials/*.html"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &partialUser{Name: "ana"}
+	tpl, err := componentTemplate(c, c.GetTemplate(), &PageControl{App: app})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tpl.Execute(buf, c); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "<div><b>ana</b></div>" {
+		t.Errorf("component rendered %q", got)
+	}
+
+	// the partials are not shared with the other apps
+	tpl, err = componentTemplate(c, c.GetTemplate(), &PageControl{App: NewApp(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := tpl.Execute(buf, c); err == nil {
+		t.Errorf("partial of another app rendered %q", buf)
+	}
+
+	if err := app.DefineTemplate("broken", `{{`); err == nil {
+		t.Error("DefineTemplate of an invalid template did not fail")
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	app := NewApp(nil)
+	if err := app.DefineTemplate("menu.html", `<nav>{{.Title}} menu</nav>`); err != nil {
+		t.Fatal(err)
+	}
+	home := &PageControl{
+		App:   app,
+		Title: "home",
+		Blocks: map[string]string{
+			"header": `<header>{{.Title}}</header>`,
+			"nav":    `{{template "menu.html" .}}`,
+			"footer": `<footer>bye</footer>`,
+		},
+	}
+	out := renderShell(t, home)
+	for _, want := range []string{"<header>home</header>", "<nav>home menu</nav>", "<footer>bye</footer>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("the shell does not include %s:\n%s", want, out)
+		}
+	}
+
+	// the blocks of a page do not leak to the other pages of the app
+	out = renderShell(t, &PageControl{App: app, Title: "about"})
+	for _, block := range []string{"<header>", "<nav>", "<footer>"} {
+		if strings.Contains(out, block) {
+			t.Errorf("the shell of another page includes %s:\n%s", block, out)
+		}
+	}
+
+	home.Blocks["header"] = `{{if}}`
+	if _, err := home.parseShell(); err == nil || !strings.HasPrefix(err.Error(), "block header:") {
+		t.Errorf("parseShell error %v, want the error of the block header", err)
+	}
+}
+
+func TestBaseTemplate(t *testing.T) {
+	pc := &PageControl{
+		App:          NewApp(nil),
+		Title:        "custom",
+		HeadCode:     `<meta name="head">`,
+		BaseTemplate: `<html>{{template "lv_head_code" .}}{{block "header" .}}default{{end}}<div id="content"></div>{{.Title}}</html>`,
+	}
+	if got := renderShell(t, pc); got != `<html><meta name="head">default<div id="content"></div>custom</html>` {
+		t.Errorf("base template rendered %q", got)
+	}
+	pc.Blocks = map[string]string{"header": "override"}
+	if got := renderShell(t, pc); !strings.Contains(got, "override") || strings.Contains(got, "default") {
+		t.Errorf("the block of the page did not override the base template: %q", got)
+	}
+}
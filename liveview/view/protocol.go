@@ -0,0 +1,123 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is the version of the messages of the client understood by the server,
+// the messages without version are of the version 1
+const ProtocolVersion = 1
+
+// the codes of the errors sent to the client as {"type":"error","code":code,"value":message}
+const (
+	ErrCodeInvalidMessage     = "invalid_message"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeUnknownType        = "unknown_type"
+	ErrCodeUnknownComponent   = "unknown_component"
+	ErrCodeUnknownEvent       = "unknown_event"
+	ErrCodeUnknownID          = "unknown_id"
+	ErrCodeInvalidModel       = "invalid_model"
+)
+
+// ProtocolError is a message of the client that can not be applied
+type ProtocolError struct {
+	Code    string
+	Message string
+}
+
+func (e *ProtocolError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func protocolError(code string, format string, args ...interface{}) *ProtocolError {
+	return &ProtocolError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Envelope is a message of the client, the fields used depend on Type:
+//
+//	data           ID, Event, Data
+//	get            IdRet, Data
+//	model          ID, Field, Data (string)
+//	upload_start   Ref, ID, Name, File, Mime, Size
+//	upload_chunk   Ref, Data (base64)
+//	upload_cancel  Ref
+//	navigate       URL, Kind
+type Envelope struct {
+	V     int         `json:"v"`
+	Type  string      `json:"type"`
+	ID    string      `json:"id"`
+	Event string      `json:"event"`
+	IdRet string      `json:"id_ret"`
+	Field string      `json:"field"`
+	Data  interface{} `json:"data"`
+	Ref   string      `json:"ref"`
+	Name  string      `json:"name"`
+	File  string      `json:"file"`
+	Mime  string      `json:"mime"`
+	Size  int64       `json:"size"`
+	URL   string      `json:"url"`
+	Kind  string      `json:"kind"`
+}
+
+// DecodeEnvelope decode and check a message of the client, the errors are *ProtocolError
+func DecodeEnvelope(msg []byte) (Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(msg, &env); err != nil {
+		return Envelope{}, protocolError(ErrCodeInvalidMessage, "%v", err)
+	}
+	if env.V == 0 {
+		env.V = 1
+	}
+	if env.V < 0 || env.V > ProtocolVersion {
+		return Envelope{}, protocolError(ErrCodeUnsupportedVersion, "version %d", env.V)
+	}
+
+	missing := func(field string) error {
+		return protocolError(ErrCodeInvalidMessage, "%s message without %s", env.Type, field)
+	}
+	switch env.Type {
+	case "data":
+		if env.ID == "" {
+			return env, missing("id")
+		}
+		if env.Event == "" {
+			return env, missing("event")
+		}
+	case "get":
+		if env.IdRet == "" {
+			return env, missing("id_ret")
+		}
+	case "model":
+		if env.ID == "" {
+			return env, missing("id")
+		}
+		if env.Field == "" {
+			return env, missing("field")
+		}
+		if _, ok := env.Data.(string); !ok && env.Data != nil {
+			return env, protocolError(ErrCodeInvalidMessage, "model message with data %T", env.Data)
+		}
+	case "upload_start", "upload_chunk", "upload_cancel":
+		if env.Ref == "" {
+			return env, missing("ref")
+		}
+		if env.Type == "upload_start" && env.Size < 0 {
+			return env, protocolError(ErrCodeInvalidMessage, "upload_start message with size %d", env.Size)
+		}
+		if env.Type == "upload_chunk" {
+			if _, ok := env.Data.(string); !ok {
+				return env, protocolError(ErrCodeInvalidMessage, "upload_chunk message without data")
+			}
+		}
+	case "navigate":
+		if env.URL == "" {
+			return env, missing("url")
+		}
+	case "":
+		return env, protocolError(ErrCodeInvalidMessage, "message without type")
+	default:
+		return env, protocolError(ErrCodeUnknownType, "%q", env.Type)
+	}
+	return env, nil
+}
@@ -0,0 +1,82 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fuzzForm struct {
+	*ComponentDriver[*fuzzForm]
+	Name  string
+	Count int
+	Tags  []string
+}
+
+func (c *fuzzForm) GetDriver() LiveDriver { return c }
+func (c *fuzzForm) Start()                {}
+func (c *fuzzForm) GetTemplate() string {
+	return `<div id="{{.IdComponent}}">{{.Name}}</div>`
+}
+func (c *fuzzForm) Save(data interface{}) {}
+
+// fuzzSession return a session without connection with the component "form" mounted
+func fuzzSession() *Session {
+	app := newApp(fiber.New(), NewHub())
+	pc := &PageControl{Path: "/", App: app}
+	s := &Session{
+		app:       app,
+		page:      pc,
+		drivers:   make(map[string]LiveDriver),
+		channelIn: make(map[string]chan interface{}),
+		scheduler: NewRenderScheduler(nil, 0),
+		uploads:   make(map[string]*UploadFile),
+	}
+	s.applyLimits(Limits{})
+	d := NewDriver("form", &fuzzForm{})
+	d.bindModels([]byte(`<input lv-model="Name"><input lv-model="Count"><input lv-model="Tags">`))
+	s.drivers["form"] = d
+	s.channelIn["ret"] = make(chan interface{}, 1)
+	return s
+}
+
+// FuzzHandleMessage feed arbitrary messages to the decoder and to the session, the
+// messages not valid are protocol errors and never a panic
+func FuzzHandleMessage(f *testing.F) {
+	for _, seed := range []string{
+		`{"v":1,"type":"data","id":"form","event":"Save","data":{"a":1}}`,
+		`{"type":"data","id":"form","event":"Start"}`,
+		`{"type":"data","id":"missing","event":"Save"}`,
+		`{"v":1,"type":"model","id":"form","field":"Count","data":"12"}`,
+		`{"v":1,"type":"model","id":"form","field":"Tags","data":"a,b"}`,
+		`{"v":1,"type":"model","id":"content","field":"Name","data":"x"}`,
+		`{"v":1,"type":"get","id_ret":"ret","data":"value"}`,
+		`{"v":1,"type":"get","id_ret":"other"}`,
+		`{"v":1,"type":"upload_start","id":"form","ref":"r1","name":"file","file":"../a.txt","mime":"text/plain","size":3}`,
+		`{"v":1,"type":"upload_chunk","ref":"r1","data":"YWJj"}`,
+		`{"v":1,"type":"upload_cancel","ref":"r1"}`,
+		`{"v":1,"type":"navigate","url":"/other?a=1","kind":"patch"}`,
+		`{"v":2,"type":"data"}`,
+		`{"type":7}`,
+		`[]`,
+		`null`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+	s := fuzzSession()
+	f.Fuzz(func(t *testing.T, msg []byte) {
+		env, err := DecodeEnvelope(msg)
+		if err != nil {
+			if _, ok := err.(*ProtocolError); !ok {
+				t.Fatalf("DecodeEnvelope(%q) error %T, want *ProtocolError", msg, err)
+			}
+			return
+		}
+		if err := s.handle(env); err != nil {
+			if _, ok := err.(*ProtocolError); !ok {
+				t.Fatalf("handle(%q) error %T, want *ProtocolError", msg, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package view
+
+import (
+	"log"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// queryBinding is a field of a component bound to a parameter of the url with the tag
+// `lv:"query=page"`
+type queryBinding struct {
+	param string
+	index []int
+}
+
+var queryBindingsByType sync.Map
+
+// queryBindings return the fields of c bound to the query of the url
+func queryBindings(c interface{}) []queryBinding {
+	t := reflect.TypeOf(c)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	if bindings, ok := queryBindingsByType.Load(t); ok {
+		return bindings.([]queryBinding)
+	}
+	var bindings []queryBinding
+	for _, field := range reflect.VisibleFields(t.Elem()) {
+		if !field.IsExported() {
+			continue
+		}
+		for _, option := range strings.Split(field.Tag.Get("lv"), ",") {
+			if param, ok := strings.CutPrefix(strings.TrimSpace(option), "query="); ok && param != "" {
+				bindings = append(bindings, queryBinding{param: param, index: field.Index})
+			}
+		}
+	}
+	queryBindingsByType.Store(t, bindings)
+	return bindings
+}
+
+// loadQuery set the bound fields present in values, with reset the fields missing in values
+// are set to their zero value. It return true when a field changed
+func (cw *ComponentDriver[T]) loadQuery(values url.Values, reset bool) bool {
+	bindings := queryBindings(cw.Component)
+	if len(bindings) == 0 {
+		return false
+	}
+	changed := false
+	v := reflect.ValueOf(cw.Component).Elem()
+	for _, b := range bindings {
+		field, err := v.FieldByIndexErr(b.index)
+		if err != nil {
+			continue
+		}
+		if !values.Has(b.param) {
+			if reset && !field.IsZero() {
+				field.Set(reflect.Zero(field.Type()))
+				changed = true
+			}
+			continue
+		}
+		value := values.Get(b.param)
+		if FieldString(field) == value {
+			continue
+		}
+		if err := SetFieldString(field, value); err != nil {
+			log.Printf("query %s of %s: %v", b.param, cw.GetIDComponet(), err)
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// syncQuery write the bound fields in the url of the browser with history.replaceState
+func (cw *ComponentDriver[T]) syncQuery() {
+	bindings := queryBindings(cw.Component)
+	if len(bindings) == 0 {
+		return
+	}
+	s := sessionFor(cw.Conn)
+	if s == nil {
+		return
+	}
+	values := make(map[string]string, len(bindings))
+	v := reflect.ValueOf(cw.Component).Elem()
+	for _, b := range bindings {
+		field, err := v.FieldByIndexErr(b.index)
+		if err != nil {
+			continue
+		}
+		if field.IsZero() {
+			values[b.param] = ""
+		} else {
+			values[b.param] = FieldString(field)
+		}
+	}
+	s.replaceQuery(values)
+}
+
+// replaceQuery set the params of the url, an empty value remove the param
+func (s *Session) replaceQuery(values map[string]string) {
+	s.mu.Lock()
+	query := s.url.Query()
+	// the query of the browser can have another order, it is compared encoded
+	current := query.Encode()
+	for param, value := range values {
+		if value == "" {
+			query.Del(param)
+		} else {
+			query.Set(param, value)
+		}
+	}
+	rawQuery := query.Encode()
+	if rawQuery == current {
+		s.mu.Unlock()
+		return
+	}
+	s.url.RawQuery = rawQuery
+	u := s.url
+	s.mu.Unlock()
+	s.send(map[string]interface{}{"type": "url", "value": u.String(), "kind": "replace"})
+}
+
+// loadQueryAll update the bound fields of every mounted component with the query of
+// the url and render the components that changed
+func (s *Session) loadQueryAll() {
+	u := s.URL()
+	values := u.Query()
+	for _, d := range s.driverList() {
+		if l, ok := d.(interface {
+			loadQuery(url.Values, bool) bool
+		}); ok && l.loadQuery(values, true) {
+			d.Commit()
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package view
+
+import (
+	"net/url"
+	"testing"
+
+	fasthttpws "github.com/fasthttp/websocket"
+)
+
+type queryBase struct {
+	Sort string `lv:"query=sort"`
+}
+
+type queryList struct {
+	*ComponentDriver[*queryList]
+	queryBase
+	Page   int    `lv:"query=page"`
+	Search string `lv:"other, query=q"`
+	Size   int    `lv:"query="`
+	hidden string `lv:"query=hidden"`
+}
+
+func (c *queryList) GetDriver() LiveDriver { return c }
+func (c *queryList) Start()                {}
+func (c *queryList) GetTemplate() string {
+	return `<div id="{{.IdComponent}}">{{.Page}} {{.Search}}</div>`
+}
+
+func (c *queryList) Next(data interface{}) {
+	c.Page++
+	c.Commit()
+}
+
+func (c *queryList) Clear(data interface{}) {
+	c.Page = 0
+	c.Search = ""
+	c.Commit()
+}
+
+func TestQueryBindings(t *testing.T) {
+	params := []string{}
+	for _, b := range queryBindings(&queryList{}) {
+		params = append(params, b.param)
+	}
+	if len(params) != 3 || params[0] != "sort" || params[1] != "page" || params[2] != "q" {
+		t.Fatalf("bindings %v, want sort, page and q", params)
+	}
+	if queryBindings(queryList{}) != nil || queryBindings(nil) != nil {
+		t.Fatal("bindings of a value that is not a pointer to a struct")
+	}
+}
+
+func TestLoadQuery(t *testing.T) {
+	d := NewDriver("query", &queryList{})
+	for _, tc := range []struct {
+		query   string
+		reset   bool
+		changed bool
+		page    int
+		search  string
+		sort    string
+	}{
+		{"page=2&q=go&sort=name", false, true, 2, "go", "name"},
+		{"page=2&q=go&sort=name", false, false, 2, "go", "name"},
+		{"page=x&q=rust", false, true, 2, "rust", "name"},
+		{"", false, false, 2, "rust", "name"},
+		{"q=rust", true, true, 0, "rust", ""},
+		{"hidden=1&Size=3", true, true, 0, "", ""},
+	} {
+		values, _ := url.ParseQuery(tc.query)
+		c := d.Component
+		if changed := d.loadQuery(values, tc.reset); changed != tc.changed || c.Page != tc.page || c.Search != tc.search || c.Sort != tc.sort {
+			t.Fatalf("loadQuery(%q, %v) = %v %d %q %q", tc.query, tc.reset, changed, c.Page, c.Search, c.Sort)
+		}
+	}
+	if d.Component.hidden != "" || d.Component.Size != 0 {
+		t.Fatal("loadQuery set a field not bound")
+	}
+}
+
+func TestSyncQuery(t *testing.T) {
+	_, conn := dialPage(t, &PageControl{}, func() LiveDriver {
+		return NewDriver("query", &queryList{})
+	})
+	messages := readMessages(conn)
+	messages()
+	send := func(msg string) []string {
+		t.Helper()
+		if err := conn.WriteMessage(fasthttpws.TextMessage, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		urls := []string{}
+		for _, m := range messages() {
+			if m["type"] == "url" {
+				urls = append(urls, m["value"].(string))
+			}
+		}
+		return urls
+	}
+
+	// the query of the browser is loaded and it is the same after the render
+	if urls := send(`{"type":"navigate","url":"/?page=3&q=go&other=1","kind":"patch"}`); len(urls) != 0 {
+		t.Fatalf("the url was replaced with %v after loading it", urls)
+	}
+	if urls := send(`{"type":"data","id":"query","event":"Next"}`); len(urls) != 1 || urls[0] != "/?other=1&page=4&q=go" {
+		t.Fatalf("urls %v after Next", urls)
+	}
+	// the zero values leave the url
+	if urls := send(`{"type":"data","id":"query","event":"Clear"}`); len(urls) != 1 || urls[0] != "/?other=1" {
+		t.Fatalf("urls %v after Clear", urls)
+	}
+	if urls := send(`{"type":"navigate","url":"/?page=7","kind":"patch"}`); len(urls) != 0 {
+		t.Fatalf("the url was replaced with %v after loading it", urls)
+	}
+	if urls := send(`{"type":"data","id":"query","event":"Next"}`); len(urls) != 1 || urls[0] != "/?page=8" {
+		t.Fatalf("urls %v after Next", urls)
+	}
+}
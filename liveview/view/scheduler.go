@@ -0,0 +1,128 @@
+package view
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+)
+
+// DefaultMaxFPS is the frame rate used when PageControl.MaxFPS is not set
+const DefaultMaxFPS = 60
+
+// RenderScheduler coalesces the messages sent to one connection and writes them
+// as a single "frame" message, at most maxFPS times per second.
+// Several fills of the same element inside one frame collapse to the last one, in the
+// position of the last one.
+type RenderScheduler struct {
+	conn     *websocket.Conn
+	interval time.Duration
+	mu       sync.Mutex
+	pending  []map[string]interface{}
+	fills    map[string]int
+	timer    *time.Timer
+	last     time.Time
+	closed   bool
+}
+
+// NewRenderScheduler create a scheduler for the connection, the drivers write through the
+// scheduler of the session of their connection
+func NewRenderScheduler(conn *websocket.Conn, maxFPS int) *RenderScheduler {
+	if maxFPS <= 0 {
+		maxFPS = DefaultMaxFPS
+	}
+	s := &RenderScheduler{
+		conn:     conn,
+		interval: time.Second / time.Duration(maxFPS),
+		fills:    make(map[string]int),
+	}
+	return s
+}
+
+// setMaxFPS change the frame rate, it is the one of the page mounted in the session
+func (s *RenderScheduler) setMaxFPS(maxFPS int) {
+	if maxFPS <= 0 {
+		maxFPS = DefaultMaxFPS
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interval = time.Second / time.Duration(maxFPS)
+}
+
+// schedulerFor return the scheduler of the session of conn
+func schedulerFor(conn *websocket.Conn) *RenderScheduler {
+	if s := sessionFor(conn); s != nil {
+		return s.scheduler
+	}
+	return nil
+}
+
+// Push queue msg for the next frame
+func (s *RenderScheduler) Push(msg map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if msg["type"] == "fill" {
+		if id, ok := msg["id"].(string); ok {
+			// the old fill is dropped and the new one goes last, after the fills of
+			// its ancestors that were queued in between
+			if idx, ok := s.fills[id]; ok {
+				s.pending[idx] = nil
+			}
+			s.fills[id] = len(s.pending)
+		}
+	}
+	s.pending = append(s.pending, msg)
+	if s.timer == nil {
+		delay := s.interval - time.Since(s.last)
+		if delay < 0 {
+			delay = 0
+		}
+		s.timer = time.AfterFunc(delay, s.Flush)
+	}
+}
+
+// Flush write the pending messages now
+func (s *RenderScheduler) Flush() {
+	s.mu.Lock()
+	pending := make([]map[string]interface{}, 0, len(s.pending))
+	for _, msg := range s.pending {
+		if msg != nil {
+			pending = append(pending, msg)
+		}
+	}
+	s.pending = nil
+	s.fills = make(map[string]int)
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.last = time.Now()
+	s.mu.Unlock()
+
+	// without connection the messages are dropped
+	if len(pending) == 0 || s.conn == nil {
+		return
+	}
+	muws.Lock()
+	defer muws.Unlock()
+	if len(pending) == 1 {
+		s.conn.WriteJSON(pending[0])
+		return
+	}
+	s.conn.WriteJSON(map[string]interface{}{"type": "frame", "messages": pending})
+}
+
+// Close drop the pending messages
+func (s *RenderScheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.pending = nil
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+}
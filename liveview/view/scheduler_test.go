@@ -0,0 +1,67 @@
+package view
+
+import (
+	"testing"
+	"time"
+)
+
+// pendingTypes return the type and id of the messages waiting for the next frame
+func pendingTypes(s *RenderScheduler) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []string{}
+	for _, msg := range s.pending {
+		if msg != nil {
+			out = append(out, msg["type"].(string)+":"+msg["id"].(string))
+		}
+	}
+	return out
+}
+
+func TestRenderSchedulerCoalesce(t *testing.T) {
+	s := NewRenderScheduler(nil, 1)
+	defer s.Close()
+	// the frame of one second was just sent, the messages wait for the next one
+	s.last = time.Now()
+	s.Push(map[string]interface{}{"type": "fill", "id": "a", "value": "1"})
+	s.Push(map[string]interface{}{"type": "fill", "id": "b", "value": "1"})
+	s.Push(map[string]interface{}{"type": "text", "id": "c", "value": "1"})
+	s.Push(map[string]interface{}{"type": "fill", "id": "a", "value": "2"})
+	want := []string{"fill:b", "text:c", "fill:a"}
+	got := pendingTypes(s)
+	if len(got) != len(want) {
+		t.Fatalf("pending %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pending %v, want %v", got, want)
+		}
+	}
+	s.mu.Lock()
+	value := s.pending[s.fills["a"]]["value"]
+	s.mu.Unlock()
+	if value != "2" {
+		t.Fatalf("the fill of a kept %v, want the last one", value)
+	}
+
+	s.Flush()
+	if got := pendingTypes(s); len(got) != 0 {
+		t.Fatalf("pending %v after Flush", got)
+	}
+	s.Push(map[string]interface{}{"type": "fill", "id": "a", "value": "3"})
+	if got := pendingTypes(s); len(got) != 1 {
+		t.Fatalf("pending %v, the fills of the old frame are forgotten", got)
+	}
+}
+
+func TestRenderSchedulerClosed(t *testing.T) {
+	s := NewRenderScheduler(nil, 0)
+	if s.interval != time.Second/DefaultMaxFPS {
+		t.Fatalf("interval %v without MaxFPS", s.interval)
+	}
+	s.Close()
+	s.Push(map[string]interface{}{"type": "fill", "id": "a"})
+	if got := pendingTypes(s); len(got) != 0 {
+		t.Fatalf("pending %v after Close", got)
+	}
+}
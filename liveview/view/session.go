@@ -0,0 +1,420 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	fasthttpws "github.com/fasthttp/websocket"
+	"github.com/gofiber/websocket/v2"
+)
+
+// Session is the state of one websocket connection, it survives the live navigation
+// between the pages registered with PageControl.Register
+type Session struct {
+	Conn *websocket.Conn
+	app  *App
+	// browser is the cookie lv_csrf of the browser that opened the connection
+	browser string
+	page    *PageControl
+	content LiveDriver
+	drivers map[string]LiveDriver
+	// muDrivers guard drivers, the drivers of the session register themselves with it
+	muDrivers sync.Mutex
+	channelIn map[string]chan interface{}
+	// muChannels guard channelIn, it is held only to add, take or remove a channel
+	muChannels sync.Mutex
+	scheduler  *RenderScheduler
+	mu         sync.Mutex
+	url        url.URL
+	closed     bool
+	uploads    map[string]*UploadFile
+	// limits, bucket and handlers are of the page mounted, guarded by mu
+	limits Limits
+	bucket *tokenBucket
+	// handlers has a slot for each event handler running, nil without MaxConcurrent
+	handlers chan struct{}
+	// protocolErrors count the messages not valid, only the read loop uses it
+	protocolErrors int
+	// modelChanges has the changes of the lv-model messages in order, the read loop
+	// queues them and runModelChanges apply them
+	modelChanges chan func()
+}
+
+// sessionFor return the session of conn in the app where it was opened
+func sessionFor(conn *websocket.Conn) *Session {
+	if conn == nil {
+		return nil
+	}
+	for _, a := range allApps() {
+		a.muSessions.RLock()
+		s := a.sessions[conn]
+		a.muSessions.RUnlock()
+		if s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// appFor return the app of the session of conn, the current app without session
+func appFor(conn *websocket.Conn) *App {
+	if s := sessionFor(conn); s != nil {
+		return s.app
+	}
+	return currentApp()
+}
+
+// sessionList return a copy of the sessions of the app
+func (a *App) sessionList() []*Session {
+	a.muSessions.RLock()
+	defer a.muSessions.RUnlock()
+	list := make([]*Session, 0, len(a.sessions))
+	for _, s := range a.sessions {
+		list = append(list, s)
+	}
+	return list
+}
+
+// driversLock return the lock of the drivers of the session of conn, mu for the
+// drivers started without session
+func driversLock(conn *websocket.Conn) *sync.Mutex {
+	if s := sessionFor(conn); s != nil {
+		return &s.muDrivers
+	}
+	return &mu
+}
+
+// channelsLock return the lock of the replies of the session of conn, muChannelIn for
+// the drivers started without session
+func channelsLock(conn *websocket.Conn) *sync.Mutex {
+	if s := sessionFor(conn); s != nil {
+		return &s.muChannels
+	}
+	return &muChannelIn
+}
+
+func newSession(conn *websocket.Conn, pc *PageControl, rawQuery string, browser string) *Session {
+	s := &Session{
+		Conn:      conn,
+		app:       pc.App,
+		browser:   browser,
+		drivers:   make(map[string]LiveDriver),
+		channelIn: make(map[string](chan interface{})),
+		scheduler: NewRenderScheduler(conn, pc.MaxFPS),
+		url:       url.URL{Path: pc.Path, RawQuery: rawQuery},
+		uploads:   make(map[string]*UploadFile),
+		// ModelChanged can wait for a reply of the browser, it can not run in the read loop
+		modelChanges: make(chan func(), maxModelChanges),
+	}
+	go s.runModelChanges()
+	s.applyLimits(pc.Limits)
+	pc.App.muSessions.Lock()
+	pc.App.sessions[conn] = s
+	pc.App.muSessions.Unlock()
+	return s
+}
+
+// URL return the url of the page shown in the browser
+func (s *Session) URL() url.URL {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.url
+}
+
+// Page return the page mounted in the session
+func (s *Session) Page() *PageControl {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.page
+}
+
+func (s *Session) send(msg map[string]interface{}) {
+	s.scheduler.Push(msg)
+}
+
+// sendNow write msg without waiting for the next frame, after the pending frame
+func (s *Session) sendNow(msg map[string]interface{}) {
+	s.scheduler.Flush()
+	if s.Conn == nil {
+		return
+	}
+	muws.Lock()
+	defer muws.Unlock()
+	s.Conn.WriteJSON(msg)
+}
+
+// mount create the layout of pc and start it in the connection
+func (s *Session) mount(pc *PageControl) {
+	content, children := buildPage(pc.App, pc.fx)
+
+	// Montar los componentes creados por la pagina para esta sesion
+	for _, v := range children {
+		content.Mount(v.GetComponet())
+	}
+	content.SetID("content")
+	if layout, ok := content.GetComponet().(*Layout); ok {
+		pc.App.Hub.Add(layout)
+	}
+
+	s.mu.Lock()
+	s.page = pc
+	s.content = content
+	s.mu.Unlock()
+
+	// Iniciar driver en goroutine
+	go func() {
+		defer HandleRecover()
+		// sin locks: Start y OnMount pueden tardar o leer el DOM, cada driver toma el
+		// lock de la sesion solo para registrarse
+		content.StartDriver(s.Conn, &s.drivers, &s.channelIn)
+
+		// la conexion se cerro o se navego a otra pagina mientras arrancaba
+		s.mu.Lock()
+		current := !s.closed && s.content == content
+		s.mu.Unlock()
+		if !current {
+			content.Destroy()
+			return
+		}
+		s.handleParams(content)
+	}()
+}
+
+// unmount destroy the layout mounted in the session
+func (s *Session) unmount() {
+	s.mu.Lock()
+	content := s.content
+	page := s.page
+	s.content = nil
+	s.mu.Unlock()
+	if content == nil {
+		return
+	}
+
+	// Desmontar el arbol de componentes, cancela los contextos de cada componente
+	func() {
+		defer HandleRecover()
+		content.Destroy()
+	}()
+
+	layout, ok := content.GetComponet().(*Layout)
+	if !ok {
+		return
+	}
+	// Eliminar el layout del hub de la app, solo si es el de esta sesion
+	page.App.Hub.remove(layout)
+
+	// Ejecutar el handler de destrucción si existe
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Println("Recovered in HandlerEventDestroy:", r)
+			}
+		}()
+		if layout.HandlerEventDestroy != nil {
+			layout.HandlerEventDestroy(content.GetIDComponet())
+		}
+	}()
+	if layout.HandlerInternalDestroy != nil {
+		layout.HandlerInternalDestroy()
+	}
+
+	fmt.Println("Delete Layout:", content.GetIDComponet())
+}
+
+func (s *Session) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.scheduler.Close()
+	// the read loop, the only sender, is done
+	close(s.modelChanges)
+	s.cancelUploads()
+	s.cancelDownloads()
+	s.unmount()
+	s.app.muSessions.Lock()
+	defer s.app.muSessions.Unlock()
+	if s.app.sessions[s.Conn] == s {
+		delete(s.app.sessions, s.Conn)
+	}
+}
+
+// handleParams invoke the HandlerParams of the layout with the query of the url
+func (s *Session) handleParams(content LiveDriver) {
+	layout, ok := content.GetComponet().(*Layout)
+	if !ok || layout.HandlerParams == nil {
+		return
+	}
+	u := s.URL()
+	go func() {
+		defer HandleRecover()
+		layout.HandlerParams(u.Query())
+	}()
+}
+
+// navigate move the session to rawURL, kind is "patch" when the page must be kept if
+// the path does not change and "navigate" when the layout must be mounted again
+func (s *Session) navigate(rawURL string, kind string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		fmt.Println("Error navegando a", rawURL, ":", err)
+		return
+	}
+	current := s.Page()
+	page := current.App.PageFor(u.Path)
+	if page == nil || !current.liveWith(page) {
+		// no es una pagina live de la misma LiveSession, el navegador la carga completa
+		// con su ruta y sus middlewares
+		s.send(map[string]interface{}{"type": "redirect", "value": u.String()})
+		return
+	}
+
+	s.mu.Lock()
+	s.url = url.URL{Path: u.Path, RawQuery: u.RawQuery}
+	samePage := s.page == page
+	content := s.content
+	s.mu.Unlock()
+
+	if samePage && kind != "navigate" {
+		s.loadQueryAll()
+		s.handleParams(content)
+		return
+	}
+	s.unmount()
+	s.applyLimits(page.Limits)
+	s.scheduler.setMaxFPS(page.MaxFPS)
+	s.send(map[string]interface{}{"type": "title", "value": page.Title})
+	// the token of the old page is not valid for the websocket of the new path
+	if !page.DisableCSRF {
+		s.send(map[string]interface{}{"type": "csrf", "value": page.csrfToken(s.browser, time.Now())})
+	}
+	s.mount(page)
+}
+
+// push change the url of the browser and navigate to it
+func (s *Session) push(rawURL string, kind string) {
+	s.send(map[string]interface{}{"type": "url", "value": rawURL, "kind": "push"})
+	s.navigate(rawURL, kind)
+}
+
+// read process the messages of the client until the connection is closed
+func (s *Session) read() {
+	var readLimit int64
+	// Leer mensajes del cliente
+	for {
+		// the limits are those of the page mounted, the navigation change them
+		if limits, _, _ := s.currentLimits(); limits.MaxMessageSize != readLimit {
+			readLimit = limits.MaxMessageSize
+			s.Conn.SetReadLimit(readLimit)
+		}
+		_, msg, err := s.Conn.ReadMessage()
+		// the connection returns the error of fasthttp/websocket, not the one declared
+		// by gofiber/websocket
+		if errors.Is(err, fasthttpws.ErrReadLimit) {
+			s.violation(ViolationSize, "")
+			break
+		}
+		if err != nil {
+			fmt.Println("Error leyendo mensaje:", err)
+			break
+		}
+
+		env, err := DecodeEnvelope(msg)
+		// the messages that can not be decoded are events too, their reply is a write
+		mtype := env.Type
+		if err != nil {
+			mtype = ""
+		}
+		if !s.allowEvent(mtype) {
+			continue
+		}
+		if err == nil {
+			err = s.handle(env)
+		}
+		if perr, ok := err.(*ProtocolError); ok {
+			s.protocolError(perr)
+		}
+	}
+}
+
+// handle apply a message of the client, the errors are *ProtocolError
+func (s *Session) handle(env Envelope) error {
+	switch env.Type {
+	case "data":
+		d := s.driver(env.ID)
+		if d == nil {
+			return protocolError(ErrCodeUnknownComponent, "%q", env.ID)
+		}
+		if e, ok := d.(interface{ hasEvent(string) bool }); ok && !e.hasEvent(env.Event) {
+			return protocolError(ErrCodeUnknownEvent, "%q of %q", env.Event, env.ID)
+		}
+		s.dispatch(d, env.Event, env.Data)
+	case "get":
+		s.muChannels.Lock()
+		ch, ok := s.channelIn[env.IdRet]
+		s.muChannels.Unlock()
+		if !ok {
+			return protocolError(ErrCodeUnknownID, "%q", env.IdRet)
+		}
+		select {
+		case ch <- env.Data:
+		default:
+			return protocolError(ErrCodeUnknownID, "%q was answered", env.IdRet)
+		}
+	case "model":
+		value, _ := env.Data.(string)
+		return s.setModel(env.ID, env.Field, value)
+	case "upload_start", "upload_chunk", "upload_cancel":
+		s.uploadMessage(env)
+	case "navigate":
+		s.navigate(env.URL, env.Kind)
+	}
+	return nil
+}
+
+// driverList return a copy of the drivers of the session
+func (s *Session) driverList() []LiveDriver {
+	s.muDrivers.Lock()
+	defer s.muDrivers.Unlock()
+	drivers := make([]LiveDriver, 0, len(s.drivers))
+	for _, d := range s.drivers {
+		drivers = append(drivers, d)
+	}
+	return drivers
+}
+
+// driver return the component mounted with id in the session, "content" is the layout
+func (s *Session) driver(id string) LiveDriver {
+	s.muDrivers.Lock()
+	d := s.drivers[id]
+	s.muDrivers.Unlock()
+	if d != nil {
+		return d
+	}
+	if id == "content" {
+		// mount and navigate replace the content under mu
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.content
+	}
+	return nil
+}
+
+// PushPatch change the url of the browser to rawURL without mounting the layout again,
+// the HandlerParams of the layout receive the new query
+func (cw *ComponentDriver[T]) PushPatch(rawURL string) {
+	if s := sessionFor(cw.Conn); s != nil {
+		s.push(rawURL, "patch")
+	}
+}
+
+// PushNavigate change the url of the browser to rawURL and mount the layout of its page
+// over the same connection
+func (cw *ComponentDriver[T]) PushNavigate(rawURL string) {
+	if s := sessionFor(cw.Conn); s != nil {
+		s.push(rawURL, "navigate")
+	}
+}
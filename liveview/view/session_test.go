@@ -0,0 +1,75 @@
+package view
+
+import (
+	"testing"
+)
+
+func TestNavigateLiveSession(t *testing.T) {
+	s := fuzzSession()
+	home := s.page
+	home.LiveSession = "public"
+	admin := &PageControl{Path: "/admin", App: s.app}
+	other := &PageControl{Path: "/other", App: s.app, LiveSession: "private"}
+	s.app.addPage(home)
+	s.app.addPage(admin)
+	s.app.addPage(other)
+
+	for _, path := range []string{"/admin", "/other", "/missing"} {
+		s.navigate(path, "navigate")
+		if s.Page() != home {
+			t.Fatalf("navigate(%q) mounted a page outside the live session", path)
+		}
+	}
+	if home.liveWith(admin) || !home.liveWith(home) || !home.liveWith(&PageControl{LiveSession: "public"}) {
+		t.Fatal("liveWith does not follow LiveSession")
+	}
+	if admin.liveWith(&PageControl{}) {
+		t.Fatal("the pages without LiveSession are in the same live session")
+	}
+}
+
+func TestNavigateLimits(t *testing.T) {
+	s := fuzzSession()
+	s.page.LiveSession = "app"
+	s.app.addPage(s.page)
+	next := &PageControl{
+		Path:        "/next",
+		App:         s.app,
+		LiveSession: "app",
+		Limits:      Limits{EventRate: 5, MaxConcurrent: 2},
+		fx:          func() LiveDriver { return NewDriver("next", &None{}) },
+	}
+	s.app.addPage(next)
+	s.navigate("/next", "navigate")
+	if s.Page() != next {
+		t.Fatal("navigate did not mount the page of the live session")
+	}
+	limits, bucket, handlers := s.currentLimits()
+	if limits.EventRate != 5 || bucket == nil || cap(handlers) != 2 {
+		t.Fatalf("the limits of the page were not applied: %+v", limits)
+	}
+	s.unmount()
+}
+
+// TestBuildPage check that every call of the page function has its own components and
+// that New outside of it does not mount anything
+func TestBuildPage(t *testing.T) {
+	outside := New("outside", &None{})
+	page := func() LiveDriver {
+		New("child", &None{})
+		return NewDriver("root", &None{})
+	}
+	_, first := buildPage(DefaultApp, page)
+	_, second := buildPage(DefaultApp, page)
+	if len(first) != 1 || len(second) != 1 || first[0] == second[0] {
+		t.Fatalf("the sessions share the components: %v %v", first, second)
+	}
+	if first[0].GetID() != "mount_span_child" {
+		t.Errorf("mount id %q, want mount_span_child", first[0].GetID())
+	}
+	for _, d := range append(first, second...) {
+		if d == outside.GetDriver() {
+			t.Error("a component created outside of the page function was mounted")
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package view
+
+import (
+	"container/list"
+	"fmt"
+	"html/template"
+	"log"
+	"reflect"
+	"sync"
+)
+
+// MaxCachedTemplates is the size of the cache of parsed templates, the least recently used
+// template is dropped when it is full. The cache is disabled when it is 0
+var MaxCachedTemplates = 1024
+
+type templateKey struct {
+	typ  reflect.Type
+	text string
+	app  *App
+	page *PageControl
+}
+
+type templateEntry struct {
+	key templateKey
+	t   *template.Template
+	err error
+}
+
+var (
+	muTemplates sync.Mutex
+	templates   map[templateKey]*list.Element = make(map[templateKey]*list.Element)
+	// templatesLRU has the entries from the most to the least recently used
+	templatesLRU = list.New()
+)
+
+// componentTemplate return the template of c parsed with the functions of page, the
+// templates are parsed once by component type, template text and page. The parse errors
+// are cached too. Without page the functions of every page of the current app are known,
+// so the template can be checked before it is mounted
+func componentTemplate(c Component, text string, page *PageControl) (*template.Template, error) {
+	app := appOf(page)
+	key := templateKey{typ: reflect.TypeOf(c), text: text, app: app, page: page}
+	muTemplates.Lock()
+	if e, ok := templates[key]; ok {
+		templatesLRU.MoveToFront(e)
+		entry := e.Value.(*templateEntry)
+		muTemplates.Unlock()
+		return entry.t, entry.err
+	}
+	muTemplates.Unlock()
+
+	funcs := templateFuncs(c, app, page)
+	if page == nil {
+		for name, fn := range app.pageFuncs() {
+			if _, ok := funcs[name]; !ok {
+				funcs[name] = fn
+			}
+		}
+	}
+	t, err := app.sharedTemplates()
+	if err == nil {
+		t, err = t.New("component").Funcs(funcs).Parse(key.text)
+	}
+	if err != nil {
+		err = fmt.Errorf("template of %s: %w", key.typ, err)
+	}
+	muTemplates.Lock()
+	defer muTemplates.Unlock()
+	if MaxCachedTemplates <= 0 {
+		return t, err
+	}
+	if e, ok := templates[key]; ok {
+		// parsed at the same time by another goroutine
+		templatesLRU.MoveToFront(e)
+		return t, err
+	}
+	for len(templates) >= MaxCachedTemplates {
+		oldest := templatesLRU.Back()
+		templatesLRU.Remove(oldest)
+		delete(templates, oldest.Value.(*templateEntry).key)
+	}
+	templates[key] = templatesLRU.PushFront(&templateEntry{key: key, t: t, err: err})
+	return t, err
+}
+
+// CheckTemplate parse the template of c and return the error, if any. The components that
+// render themselves have nothing to check
+func CheckTemplate(c Component) (err error) {
+	switch c.(type) {
+	case Renderer, ContextRenderer:
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("template of %T: %v", c, r)
+		}
+	}()
+	_, err = componentTemplate(c, c.GetTemplate(), nil)
+	return err
+}
+
+// ClearTemplateCache forget every parsed template
+func ClearTemplateCache() {
+	muTemplates.Lock()
+	defer muTemplates.Unlock()
+	templates = make(map[templateKey]*list.Element)
+	templatesLRU.Init()
+}
+
+// clearTemplates forget the templates parsed for app, the cache of the other apps is kept
+func clearTemplates(app *App) {
+	muTemplates.Lock()
+	defer muTemplates.Unlock()
+	for key, e := range templates {
+		if key.app == app {
+			templatesLRU.Remove(e)
+			delete(templates, key)
+		}
+	}
+}
+
+func logTemplateError(id string, c Component) {
+	if err := CheckTemplate(c); err != nil {
+		log.Printf("component %s: %v", id, err)
+	}
+}
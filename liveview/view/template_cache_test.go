@@ -0,0 +1,44 @@
+package view
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+type benchClock struct {
+	*ComponentDriver[*benchClock]
+	Now time.Time
+}
+
+func (c *benchClock) GetDriver() LiveDriver { return c }
+func (c *benchClock) Start()                {}
+func (c *benchClock) GetTemplate() string {
+	return `<div id="{{.IdComponent}}"><span>{{formatDate .Now "15:04:05"}}</span>{{if .Now.IsZero}}-{{end}}</div>`
+}
+
+// BenchmarkRender render a clock with and without the template cache
+func BenchmarkRender(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		max  int
+	}{{"cache", 1024}, {"no_cache", 0}} {
+		b.Run(bc.name, func(b *testing.B) {
+			defer func(max int) {
+				MaxCachedTemplates = max
+				ClearTemplateCache()
+			}(MaxCachedTemplates)
+			MaxCachedTemplates = bc.max
+			ClearTemplateCache()
+			c := &benchClock{Now: time.Now()}
+			d := NewDriver("bench_clock", c)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := d.render(io.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package view
+
+import (
+	"math/rand"
+	"time"
+)
+
+// TimerOption configure a timer of a Layout
+type TimerOption func(*layoutTimer)
+
+// WithJitter add a random delay between 0 and jitter to every run of the timer,
+// useful to spread the work of many layouts created at the same time
+func WithJitter(jitter time.Duration) TimerOption {
+	return func(lt *layoutTimer) {
+		lt.jitter = jitter
+	}
+}
+
+type layoutTimer struct {
+	interval   time.Duration
+	schedule   *CronSchedule
+	jitter     time.Duration
+	fx         func()
+	timer      *time.Timer
+	generation int
+}
+
+func (lt *layoutTimer) delay(now time.Time) time.Duration {
+	d := lt.interval
+	if lt.schedule != nil {
+		next := lt.schedule.Next(now)
+		if next.IsZero() {
+			return -1
+		}
+		d = next.Sub(now)
+	}
+	if lt.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(lt.jitter)))
+	}
+	return d
+}
+
+// AddTimer run fx every interval until the timer is cancelled or the layout destroyed,
+// a timer with the same name is replaced
+func (t *Layout) AddTimer(name string, interval time.Duration, fx func(), opts ...TimerOption) {
+	if interval <= 0 {
+		return
+	}
+	lt := &layoutTimer{interval: interval, fx: fx}
+	for _, opt := range opts {
+		opt(lt)
+	}
+	t.startTimer(name, lt)
+}
+
+// AddCron run fx on the times matching the cron expression spec, see ParseCron
+func (t *Layout) AddCron(name string, spec string, fx func(), opts ...TimerOption) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	lt := &layoutTimer{schedule: schedule, fx: fx}
+	for _, opt := range opts {
+		opt(lt)
+	}
+	t.startTimer(name, lt)
+	return nil
+}
+
+// ResetTimer restart the countdown of the timer, it return false when it does not exist
+func (t *Layout) ResetTimer(name string) bool {
+	t.muTimers.Lock()
+	defer t.muTimers.Unlock()
+	lt, ok := t.timers[name]
+	if !ok {
+		return false
+	}
+	t.schedule(name, lt)
+	return true
+}
+
+// CancelTimer stop and remove the timer, it return false when it does not exist
+func (t *Layout) CancelTimer(name string) bool {
+	t.muTimers.Lock()
+	defer t.muTimers.Unlock()
+	lt, ok := t.timers[name]
+	if !ok {
+		return false
+	}
+	lt.timer.Stop()
+	lt.generation++
+	delete(t.timers, name)
+	return true
+}
+
+// TimerNames return the names of the active timers
+func (t *Layout) TimerNames() []string {
+	t.muTimers.Lock()
+	defer t.muTimers.Unlock()
+	names := make([]string, 0, len(t.timers))
+	for name := range t.timers {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (t *Layout) startTimer(name string, lt *layoutTimer) {
+	t.muTimers.Lock()
+	defer t.muTimers.Unlock()
+	if t.timersStopped {
+		return
+	}
+	if t.timers == nil {
+		t.timers = make(map[string]*layoutTimer)
+	}
+	if old, ok := t.timers[name]; ok {
+		old.timer.Stop()
+		old.generation++
+	}
+	t.timers[name] = lt
+	t.schedule(name, lt)
+}
+
+// schedule arm the next run of lt, the caller must hold muTimers
+func (t *Layout) schedule(name string, lt *layoutTimer) {
+	if lt.timer != nil {
+		lt.timer.Stop()
+	}
+	lt.generation++
+	generation := lt.generation
+	d := lt.delay(time.Now())
+	if d < 0 {
+		delete(t.timers, name)
+		return
+	}
+	lt.timer = time.AfterFunc(d, func() {
+		t.muTimers.Lock()
+		if t.timersStopped || lt.generation != generation {
+			t.muTimers.Unlock()
+			return
+		}
+		t.muTimers.Unlock()
+
+		func() {
+			defer HandleRecover()
+			t.Do(lt.fx)
+		}()
+
+		t.muTimers.Lock()
+		defer t.muTimers.Unlock()
+		if !t.timersStopped && lt.generation == generation {
+			t.schedule(name, lt)
+		}
+	})
+}
+
+// stopTimers cancel every timer, no timer can be added after it
+func (t *Layout) stopTimers() {
+	t.muTimers.Lock()
+	defer t.muTimers.Unlock()
+	t.timersStopped = true
+	for name, lt := range t.timers {
+		lt.timer.Stop()
+		lt.generation++
+		delete(t.timers, name)
+	}
+}
@@ -0,0 +1,439 @@
+package view
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxUploadSize is the size limit of a file when UploadConfig.MaxSize is 0
+const DefaultMaxUploadSize int64 = 10 << 20
+
+// UploadChunkSize is the size of the pieces of a file sent by the browser
+const UploadChunkSize = 64 << 10
+
+// UploadConfig are the rules of the files of an input with lv-upload="name"
+type UploadConfig struct {
+	// MaxSize is the size limit of each file in bytes, DefaultMaxUploadSize when it is 0
+	MaxSize int64
+	// MaxFiles is the number of files of the input being uploaded at the same time, 1 when it is 0
+	MaxFiles int
+	// Accept are the types allowed, as mime types ("image/png", "image/*") or extensions
+	// (".pdf"). Every type is allowed when it is empty
+	Accept []string
+	// Writer return where the file is written, a temp file when it is nil
+	Writer func(f *UploadFile) (io.WriteCloser, error)
+	// OnProgress is invoked after each piece is written, with a copy of the state of the file
+	OnProgress func(f *UploadFile)
+	// OnDone is invoked when the file is complete, canceled or failed, with a copy of the
+	// state of the file
+	OnDone func(f *UploadFile)
+}
+
+// UploadFile is a file being uploaded by the browser. The uploads are written by the
+// connection, so the files given by Uploads and the callbacks are copies of their state
+// at that moment
+type UploadFile struct {
+	Ref      string
+	Input    string
+	Name     string
+	Type     string
+	Size     int64
+	Received int64
+	// Path is the temp file, when UploadConfig has no Writer
+	Path     string
+	Done     bool
+	Canceled bool
+	Err      error
+
+	mu     sync.Mutex
+	w      io.WriteCloser
+	config *UploadConfig
+	// source is the file being uploaded, when this is a copy
+	source *UploadFile
+}
+
+// snapshot return a copy of the state of f, the caller must hold mu
+func (f *UploadFile) snapshot() *UploadFile {
+	return &UploadFile{
+		Ref:      f.Ref,
+		Input:    f.Input,
+		Name:     f.Name,
+		Type:     f.Type,
+		Size:     f.Size,
+		Received: f.Received,
+		Path:     f.Path,
+		Done:     f.Done,
+		Canceled: f.Canceled,
+		Err:      f.Err,
+		source:   f,
+	}
+}
+
+// live return the file being uploaded
+func (f *UploadFile) live() *UploadFile {
+	if f.source != nil {
+		return f.source
+	}
+	return f
+}
+
+// Progress return the percent received
+func (f *UploadFile) Progress() int {
+	if f.Size <= 0 {
+		return 100
+	}
+	return int(f.Received * 100 / f.Size)
+}
+
+// Open return the temp file received
+func (f *UploadFile) Open() (*os.File, error) {
+	if f.Path == "" {
+		return nil, fmt.Errorf("upload %s has no temp file", f.Name)
+	}
+	return os.Open(f.Path)
+}
+
+// Remove delete the temp file
+func (f *UploadFile) Remove() {
+	src := f.live()
+	if src != f {
+		f.Path = ""
+	}
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	src.remove()
+}
+
+// remove delete the temp file, the caller must hold mu
+func (f *UploadFile) remove() {
+	if f.Path != "" {
+		os.Remove(f.Path)
+		f.Path = ""
+	}
+}
+
+type uploads struct {
+	mu     sync.Mutex
+	config map[string]*UploadConfig
+	files  map[string][]*UploadFile
+}
+
+// AllowUpload accept the files of the inputs lv-upload="name" of the component
+func (cw *ComponentDriver[T]) AllowUpload(name string, config UploadConfig) {
+	cw.uploads.mu.Lock()
+	defer cw.uploads.mu.Unlock()
+	if cw.uploads.config == nil {
+		cw.uploads.config = make(map[string]*UploadConfig)
+	}
+	cw.uploads.config[name] = &config
+}
+
+// Uploads return a copy of the files of the input lv-upload="name", complete or not
+func (cw *ComponentDriver[T]) Uploads(name string) []*UploadFile {
+	cw.uploads.mu.Lock()
+	files := append([]*UploadFile(nil), cw.uploads.files[name]...)
+	cw.uploads.mu.Unlock()
+	for i, f := range files {
+		f.mu.Lock()
+		files[i] = f.snapshot()
+		f.mu.Unlock()
+	}
+	return files
+}
+
+// ClearUploads forget the files of the input lv-upload="name" and delete their temp files
+func (cw *ComponentDriver[T]) ClearUploads(name string) {
+	cw.uploads.mu.Lock()
+	files := cw.uploads.files[name]
+	delete(cw.uploads.files, name)
+	cw.uploads.mu.Unlock()
+	for _, f := range files {
+		f.cancel()
+		f.Remove()
+	}
+}
+
+// clearAllUploads forget every file of the component, when it is destroyed
+func (cw *ComponentDriver[T]) clearAllUploads() {
+	cw.uploads.mu.Lock()
+	names := make([]string, 0, len(cw.uploads.files))
+	for name := range cw.uploads.files {
+		names = append(names, name)
+	}
+	cw.uploads.mu.Unlock()
+	for _, name := range names {
+		cw.ClearUploads(name)
+	}
+}
+
+// startUpload check the file against the rules of the input and open its writer
+func (cw *ComponentDriver[T]) startUpload(f *UploadFile) error {
+	cw.uploads.mu.Lock()
+	defer cw.uploads.mu.Unlock()
+	config, ok := cw.uploads.config[f.Input]
+	if !ok {
+		return fmt.Errorf("upload %q is not allowed", f.Input)
+	}
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxUploadSize
+	}
+	if f.Size < 0 || f.Size > maxSize {
+		return fmt.Errorf("file too large, the limit is %d bytes", maxSize)
+	}
+	if !acceptType(config.Accept, f.Name, f.Type) {
+		return fmt.Errorf("type of file %q not allowed", f.Name)
+	}
+	maxFiles := config.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = 1
+	}
+	active := 0
+	for _, other := range cw.uploads.files[f.Input] {
+		other.mu.Lock()
+		if !other.Done {
+			active++
+		}
+		other.mu.Unlock()
+	}
+	if active >= maxFiles {
+		return fmt.Errorf("too many files, the limit is %d", maxFiles)
+	}
+
+	if config.Writer != nil {
+		w, err := config.Writer(f.snapshot())
+		if err != nil {
+			return err
+		}
+		f.w = w
+	} else {
+		tmp, err := os.CreateTemp("", "liveview-upload-*"+filepath.Ext(f.Name))
+		if err != nil {
+			return err
+		}
+		f.w = tmp
+		f.Path = tmp.Name()
+	}
+	f.config = config
+	if cw.uploads.files == nil {
+		cw.uploads.files = make(map[string][]*UploadFile)
+	}
+	cw.uploads.files[f.Input] = append(cw.uploads.files[f.Input], f)
+	return nil
+}
+
+func acceptType(accept []string, name string, mimeType string) bool {
+	if len(accept) == 0 {
+		return true
+	}
+	ext := strings.ToLower(path.Ext(name))
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(ext)
+	}
+	mimeType = strings.ToLower(mimeType)
+	for _, a := range accept {
+		a = strings.ToLower(strings.TrimSpace(a))
+		switch {
+		case strings.HasPrefix(a, "."):
+			if a == ext {
+				return true
+			}
+		case strings.HasSuffix(a, "/*"):
+			if strings.HasPrefix(mimeType, strings.TrimSuffix(a, "*")) {
+				return true
+			}
+		case a == mimeType:
+			return true
+		}
+	}
+	return false
+}
+
+// write add a piece of the file, it return the bytes received and true when the file
+// is complete
+func (f *UploadFile) write(data []byte) (int64, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Done {
+		return f.Received, false, fmt.Errorf("upload finished")
+	}
+	if f.Received+int64(len(data)) > f.Size {
+		return f.Received, false, fmt.Errorf("file larger than announced")
+	}
+	if _, err := f.w.Write(data); err != nil {
+		return f.Received, false, err
+	}
+	f.Received += int64(len(data))
+	if f.config.OnProgress != nil {
+		f.config.OnProgress(f.snapshot())
+	}
+	if f.Received < f.Size {
+		return f.Received, false, nil
+	}
+	f.finish(f.w.Close())
+	return f.Received, true, nil
+}
+
+// finish close the upload with err and invoke OnDone, the caller must hold mu
+func (f *UploadFile) finish(err error) {
+	if f.Done {
+		return
+	}
+	f.Done = true
+	f.Err = err
+	if err != nil || f.Canceled {
+		f.remove()
+	}
+	if f.config.OnDone != nil {
+		done := f.snapshot()
+		go func() {
+			defer HandleRecover()
+			f.config.OnDone(done)
+		}()
+	}
+}
+
+func (f *UploadFile) cancel() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Done {
+		return
+	}
+	f.Canceled = true
+	f.w.Close()
+	f.finish(fmt.Errorf("upload canceled"))
+}
+
+// fail close the upload with err
+func (f *UploadFile) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Done {
+		return
+	}
+	f.w.Close()
+	f.finish(err)
+}
+
+type uploader interface {
+	startUpload(f *UploadFile) error
+}
+
+// uploadMessage apply the messages of the uploads of the browser: "upload_start",
+// "upload_chunk" and "upload_cancel"
+func (s *Session) uploadMessage(env Envelope) {
+	ref := env.Ref
+	s.mu.Lock()
+	f := s.uploads[ref]
+	s.mu.Unlock()
+
+	switch env.Type {
+	case "upload_start":
+		if f != nil {
+			s.uploadReply(ref, "error", "duplicated upload")
+			return
+		}
+		f = &UploadFile{Ref: ref}
+		f.Input = env.Name
+		f.Name = path.Base(filepath.ToSlash(env.File))
+		f.Type = env.Mime
+		f.Size = env.Size
+		u, ok := s.driver(env.ID).(uploader)
+		if !ok {
+			s.uploadReply(ref, "error", "unknown component")
+			return
+		}
+		if err := u.startUpload(f); err != nil {
+			s.uploadReply(ref, "error", err.Error())
+			return
+		}
+		s.mu.Lock()
+		s.uploads[ref] = f
+		s.mu.Unlock()
+		if f.Size == 0 {
+			s.uploadChunk(f, nil)
+			return
+		}
+		s.uploadReply(ref, "ack", 0)
+	case "upload_chunk":
+		if f == nil {
+			s.uploadReply(ref, "error", "unknown upload")
+			return
+		}
+		encoded, _ := env.Data.(string)
+		chunk, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			s.uploadFailed(f, err)
+			return
+		}
+		s.uploadChunk(f, chunk)
+	case "upload_cancel":
+		if f == nil {
+			return
+		}
+		s.forgetUpload(f)
+		f.cancel()
+	}
+}
+
+func (s *Session) uploadChunk(f *UploadFile, chunk []byte) {
+	received, done, err := f.write(chunk)
+	if err != nil {
+		s.uploadFailed(f, err)
+		return
+	}
+	if done {
+		s.forgetUpload(f)
+		s.uploadReply(f.Ref, "done", received)
+		return
+	}
+	s.uploadReply(f.Ref, "ack", received)
+}
+
+func (s *Session) uploadFailed(f *UploadFile, err error) {
+	log.Println("upload", f.Name, err)
+	s.forgetUpload(f)
+	f.fail(err)
+	s.uploadReply(f.Ref, "error", err.Error())
+}
+
+func (s *Session) forgetUpload(f *UploadFile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploads, f.Ref)
+}
+
+// cancelUploads stop the uploads not complete, when the connection is closed
+func (s *Session) cancelUploads() {
+	s.mu.Lock()
+	files := s.uploads
+	s.uploads = make(map[string]*UploadFile)
+	s.mu.Unlock()
+	for _, f := range files {
+		f.cancel()
+	}
+}
+
+// uploadReply tell the browser the state of an upload: "ack" with the bytes received
+// asks for the next piece, "done", or "error" with the message
+func (s *Session) uploadReply(ref string, kind string, value interface{}) {
+	s.sendNow(map[string]interface{}{"type": "upload", "ref": ref, "kind": kind, "value": value})
+}
+
+// CancelUpload stop the upload of the browser, the file is discarded
+func (cw *ComponentDriver[T]) CancelUpload(f *UploadFile) {
+	f = f.live()
+	if s := sessionFor(cw.Conn); s != nil {
+		s.forgetUpload(f)
+		s.uploadReply(f.Ref, "cancel", nil)
+	}
+	f.cancel()
+}
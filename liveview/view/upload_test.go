@@ -0,0 +1,122 @@
+package view
+
+import (
+	"encoding/base64"
+	"os"
+	"sync"
+	"testing"
+)
+
+func uploadEnv(kind, ref string, data string) Envelope {
+	env := Envelope{V: 1, Type: kind, ID: "form", Ref: ref, Name: "file"}
+	if kind == "upload_start" {
+		env.File = "../" + ref + ".txt"
+		env.Mime = "text/plain"
+		env.Size = int64(len(data))
+	} else {
+		env.Data = base64.StdEncoding.EncodeToString([]byte(data))
+	}
+	return env
+}
+
+func TestUpload(t *testing.T) {
+	s := fuzzSession()
+	d := s.drivers["form"].(*ComponentDriver[*fuzzForm])
+	done := make(chan *UploadFile, 1)
+	d.AllowUpload("file", UploadConfig{MaxSize: 8, MaxFiles: 1, Accept: []string{".txt"}, OnDone: func(f *UploadFile) { done <- f }})
+
+	s.uploadMessage(uploadEnv("upload_start", "r1", "abcdef"))
+	s.uploadMessage(uploadEnv("upload_start", "r2", "x"))
+	if files := d.Uploads("file"); len(files) != 1 || files[0].Name != "r1.txt" {
+		t.Fatalf("uploads %v, the second file is over MaxFiles", files)
+	}
+
+	// the copies are read while the connection writes the file
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			for _, f := range d.Uploads("file") {
+				_ = f.Progress()
+			}
+		}
+	}()
+	s.uploadMessage(uploadEnv("upload_chunk", "r1", "abc"))
+	s.uploadMessage(uploadEnv("upload_chunk", "r1", "def"))
+	wg.Wait()
+
+	f := <-done
+	if !f.Done || f.Err != nil || f.Received != 6 || f.Progress() != 100 {
+		t.Fatalf("upload finished with %+v", f)
+	}
+	r, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	d.ClearUploads("file")
+	if len(d.Uploads("file")) != 0 {
+		t.Fatal("ClearUploads kept the files")
+	}
+	if _, err := os.Stat(f.Path); !os.IsNotExist(err) {
+		t.Fatalf("the temp file was not removed: %v", err)
+	}
+}
+
+func TestUploadCancel(t *testing.T) {
+	s := fuzzSession()
+	d := s.drivers["form"].(*ComponentDriver[*fuzzForm])
+	d.AllowUpload("file", UploadConfig{})
+	s.uploadMessage(uploadEnv("upload_start", "r1", "abcdef"))
+	s.uploadMessage(uploadEnv("upload_chunk", "r1", "abc"))
+	f := d.Uploads("file")[0]
+	d.CancelUpload(f)
+	if f.Done {
+		t.Fatal("the copy changed after it was taken")
+	}
+	f = d.Uploads("file")[0]
+	if !f.Done || !f.Canceled || f.Path != "" {
+		t.Fatalf("upload canceled with %+v", f)
+	}
+	s.uploadMessage(uploadEnv("upload_chunk", "r1", "def"))
+	if f = d.Uploads("file")[0]; f.Received != 3 {
+		t.Fatalf("received %d after cancel", f.Received)
+	}
+}
+
+func TestUploadRules(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config UploadConfig
+		file   string
+		size   int64
+		mime   string
+		err    bool
+	}{
+		{"any", UploadConfig{}, "a.bin", 10, "", false},
+		{"too large", UploadConfig{MaxSize: 5}, "a.bin", 10, "", true},
+		{"default size", UploadConfig{}, "a.bin", DefaultMaxUploadSize + 1, "", true},
+		{"negative size", UploadConfig{}, "a.bin", -1, "", true},
+		{"extension", UploadConfig{Accept: []string{".PDF"}}, "a.pdf", 1, "", false},
+		{"other extension", UploadConfig{Accept: []string{".pdf"}}, "a.png", 1, "image/png", true},
+		{"mime prefix", UploadConfig{Accept: []string{"image/*"}}, "a", 1, "image/png", false},
+		{"mime by extension", UploadConfig{Accept: []string{"image/png"}}, "a.png", 1, "", false},
+		{"other mime", UploadConfig{Accept: []string{"image/*"}}, "a.txt", 1, "text/plain", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewDriver("rules", &fuzzForm{})
+			d.AllowUpload("file", tc.config)
+			f := &UploadFile{Ref: "r", Input: "file", Name: tc.file, Type: tc.mime, Size: tc.size}
+			err := d.startUpload(f)
+			if (err != nil) != tc.err {
+				t.Fatalf("startUpload error %v", err)
+			}
+			d.ClearUploads("file")
+		})
+	}
+	d := NewDriver("rules", &fuzzForm{})
+	if err := d.startUpload(&UploadFile{Input: "missing"}); err == nil {
+		t.Fatal("upload of an input not allowed")
+	}
+}
@@ -0,0 +1,298 @@
+package view
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldErrors are the messages of the fields not valid, by field path like "Address.City"
+type FieldErrors map[string]string
+
+// ValidatorFunc check value against the rule with param, the text after "=" in the tag
+type ValidatorFunc func(value reflect.Value, param string) error
+
+var (
+	muValidators sync.RWMutex
+	validators   = map[string]ValidatorFunc{
+		"required": validateRequired,
+		"min":      validateMin,
+		"max":      validateMax,
+		"pattern":  validatePattern,
+		"email":    validateEmail,
+	}
+	muPatterns sync.Mutex
+	patterns   = map[string]*regexp.Regexp{}
+	emailRegex = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+)
+
+// RegisterValidator add the rule name for the validate tags, for example
+// RegisterValidator("even", fx) is used as `validate:"even"`
+func RegisterValidator(name string, fx ValidatorFunc) {
+	muValidators.Lock()
+	defer muValidators.Unlock()
+	validators[name] = fx
+}
+
+// Validate check the fields of the struct v with the tag validate, for example
+// `validate:"required,min=3,max=20"`. The nested structs are checked too. The pattern rule
+// takes the rest of the tag, so it is the last one: `validate:"required,pattern=^[a-z,]+$"`
+func Validate(v interface{}) FieldErrors {
+	errs := FieldErrors{}
+	validateStruct(reflect.ValueOf(v), "", errs)
+	return errs
+}
+
+// CheckRules check the validate tags of the struct v and its nested structs: the rules
+// must be registered and their params valid. The rules unknown are ignored by Validate,
+// so the tags are checked when the form is built
+func CheckRules(v interface{}) error {
+	return checkRules(reflect.TypeOf(v), "", map[reflect.Type]bool{})
+}
+
+// checkRules check the tags of t once, visited are the types already checked so the
+// types that refer to themselves end
+func checkRules(t reflect.Type, prefix string, visited map[reflect.Type]bool) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t == timeType || visited[t] {
+		return nil
+	}
+	visited[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		path := prefix + f.Name
+		if tag := f.Tag.Get("validate"); tag != "" && tag != "-" {
+			for _, rule := range splitRules(tag) {
+				name, param, _ := strings.Cut(rule, "=")
+				muValidators.RLock()
+				_, ok := validators[name]
+				muValidators.RUnlock()
+				if !ok {
+					return fmt.Errorf("%s: unknown rule %q", path, name)
+				}
+				if err := checkParam(name, param); err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+			}
+		}
+		if err := checkRules(f.Type, path+".", visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkParam check the params of the rules of the package
+func checkParam(name string, param string) error {
+	switch name {
+	case "min", "max":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("invalid %s %q", name, param)
+		}
+	case "pattern":
+		if _, err := regexp.Compile(param); err != nil {
+			return fmt.Errorf("invalid pattern %q", param)
+		}
+	}
+	return nil
+}
+
+// ValidateField check only the field of v named by the dotted path, it return the message
+// of the first rule not met or ""
+func ValidateField(v interface{}, path string) string {
+	value := reflect.ValueOf(v)
+	var field reflect.StructField
+	for _, name := range strings.Split(path, ".") {
+		value = reflect.Indirect(value)
+		if value.Kind() != reflect.Struct {
+			return ""
+		}
+		f, ok := value.Type().FieldByName(name)
+		if !ok {
+			return ""
+		}
+		field = f
+		value = value.FieldByIndex(f.Index)
+	}
+	if err := validateValue(value, field.Tag.Get("validate")); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateStruct(v reflect.Value, prefix string, errs FieldErrors) {
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		value := v.Field(i)
+		path := prefix + f.Name
+		if err := validateValue(value, f.Tag.Get("validate")); err != nil {
+			errs[path] = err.Error()
+			continue
+		}
+		inner := reflect.Indirect(value)
+		if inner.Kind() == reflect.Struct && inner.Type() != timeType {
+			validateStruct(inner, path+".", errs)
+		}
+	}
+}
+
+// validateValue check the rules of tag, the first one not met is returned
+func validateValue(value reflect.Value, tag string) error {
+	if tag == "" || tag == "-" {
+		return nil
+	}
+	for _, rule := range splitRules(tag) {
+		name, param, _ := strings.Cut(rule, "=")
+		muValidators.RLock()
+		fx, ok := validators[name]
+		muValidators.RUnlock()
+		if !ok {
+			// a configuration error reported by CheckRules, not a message for the user
+			continue
+		}
+		if name != "required" && isEmpty(value) {
+			// the empty values are only checked by required
+			continue
+		}
+		if err := fx(value, param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitRules(tag string) []string {
+	rules := []string{}
+	for tag != "" {
+		if strings.HasPrefix(tag, "pattern=") {
+			return append(rules, tag)
+		}
+		rule, rest, _ := strings.Cut(tag, ",")
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+		tag = strings.TrimSpace(rest)
+	}
+	return rules
+}
+
+func isEmpty(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+func validateRequired(value reflect.Value, param string) error {
+	if isEmpty(value) || (value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "") {
+		return fmt.Errorf("is required")
+	}
+	return nil
+}
+
+// size is the length of strings and lists, and the value of numbers
+func size(value reflect.Value) (float64, bool) {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// unit is the unit of the size of strings and lists, "" for numbers
+func unit(value reflect.Value) string {
+	switch reflect.Indirect(value).Kind() {
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return "items"
+	}
+	return ""
+}
+
+func validateMin(value reflect.Value, param string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("invalid min %q", param)
+	}
+	n, ok := size(value)
+	if !ok || n >= limit {
+		return nil
+	}
+	if u := unit(value); u != "" {
+		return fmt.Errorf("must have at least %s %s", param, u)
+	}
+	return fmt.Errorf("must be at least %s", param)
+}
+
+func validateMax(value reflect.Value, param string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return fmt.Errorf("invalid max %q", param)
+	}
+	n, ok := size(value)
+	if !ok || n <= limit {
+		return nil
+	}
+	if u := unit(value); u != "" {
+		return fmt.Errorf("must have at most %s %s", param, u)
+	}
+	return fmt.Errorf("must be at most %s", param)
+}
+
+func validatePattern(value reflect.Value, param string) error {
+	muPatterns.Lock()
+	re, ok := patterns[param]
+	if !ok {
+		var err error
+		if re, err = regexp.Compile(param); err != nil {
+			muPatterns.Unlock()
+			return fmt.Errorf("invalid pattern %q", param)
+		}
+		patterns[param] = re
+	}
+	muPatterns.Unlock()
+	if !re.MatchString(fmt.Sprint(reflect.Indirect(value).Interface())) {
+		return fmt.Errorf("has an invalid format")
+	}
+	return nil
+}
+
+func validateEmail(value reflect.Value, param string) error {
+	if !emailRegex.MatchString(fmt.Sprint(reflect.Indirect(value).Interface())) {
+		return fmt.Errorf("is not a valid email")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package view
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type validAddress struct {
+	City string `validate:"required"`
+	Zip  string `validate:"pattern=^[0-9]{4}$"`
+}
+
+type validUser struct {
+	Name    string   `validate:"required,min=3,max=10"`
+	Email   string   `validate:"email"`
+	Age     int      `validate:"min=18,max=99"`
+	Score   *float64 `validate:"required,max=10"`
+	Tags    []string `validate:"max=2"`
+	Code    string   `validate:"pattern=^[a-z,]+$"`
+	Address validAddress
+	Born    time.Time `validate:"required"`
+	skipped string    `validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+	score := 12.0
+	for _, tc := range []struct {
+		name string
+		user validUser
+		want FieldErrors
+	}{
+		{"empty", validUser{}, FieldErrors{
+			"Name": "is required", "Score": "is required", "Address.City": "is required", "Born": "is required",
+		}},
+		{"valid", validUser{
+			Name: "ana", Email: "ana@example.com", Age: 30, Score: new(float64), Tags: []string{"a"},
+			Code: "a,b", Address: validAddress{City: "Rosario", Zip: "2000"}, Born: time.Now(),
+		}, FieldErrors{}},
+		{"invalid", validUser{
+			Name: "an", Email: "ana", Age: 12, Score: &score, Tags: []string{"a", "b", "c"},
+			Code: "A", Address: validAddress{City: " ", Zip: "20"}, Born: time.Now(),
+		}, FieldErrors{
+			"Name":         "must have at least 3 characters",
+			"Email":        "is not a valid email",
+			"Age":          "must be at least 18",
+			"Score":        "must be at most 10",
+			"Tags":         "must have at most 2 items",
+			"Code":         "has an invalid format",
+			"Address.City": "is required",
+			"Address.Zip":  "has an invalid format",
+		}},
+		{"max length in runes", validUser{
+			Name: "ñandúñandúñ", Score: new(float64), Address: validAddress{City: "x"}, Born: time.Now(),
+		}, FieldErrors{"Name": "must have at most 10 characters"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Validate(&tc.user); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Validate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateField(t *testing.T) {
+	u := validUser{Name: "x", Address: validAddress{Zip: "1"}}
+	for path, want := range map[string]string{
+		"Name":         "must have at least 3 characters",
+		"Email":        "",
+		"Address.City": "is required",
+		"Address.Zip":  "has an invalid format",
+		"Missing":      "",
+		"Name.Inner":   "",
+	} {
+		if got := ValidateField(&u, path); got != want {
+			t.Errorf("ValidateField(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	RegisterValidator("even_test", func(value reflect.Value, param string) error {
+		if value.Int()%2 != 0 {
+			return fmt.Errorf("must be even")
+		}
+		return nil
+	})
+	type even struct {
+		N int `validate:"even_test"`
+	}
+	if err := CheckRules(&even{}); err != nil {
+		t.Fatal(err)
+	}
+	if errs := Validate(&even{N: 3}); errs["N"] != "must be even" {
+		t.Fatalf("the registered rule was not applied: %v", errs)
+	}
+	if err := CheckRules(&validUser{}); err != nil {
+		t.Fatal(err)
+	}
+
+	type unknown struct {
+		N int `validate:"required,odd"`
+	}
+	type badMin struct {
+		N int `validate:"min=x"`
+	}
+	type badPattern struct {
+		S string `validate:"pattern=["`
+	}
+	type nested struct {
+		Inner *badMin
+	}
+	type node struct {
+		Name string `validate:"required"`
+		Next *node
+		Prev *node `validate:"odd"`
+	}
+	for _, tc := range []struct {
+		v    interface{}
+		want string
+	}{
+		{&unknown{}, `N: unknown rule "odd"`},
+		{&badMin{}, `N: invalid min "x"`},
+		{&badPattern{}, `S: invalid pattern "["`},
+		{&nested{}, `Inner.N: invalid min "x"`},
+		{&node{}, `Prev: unknown rule "odd"`},
+	} {
+		err := CheckRules(tc.v)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("CheckRules(%T) = %v, want %q", tc.v, err, tc.want)
+		}
+	}
+	// the unknown rules are a configuration error, Validate ignores them
+	if errs := Validate(&unknown{N: 1}); len(errs) != 0 {
+		t.Fatalf("Validate applied an unknown rule: %v", errs)
+	}
+}
+
+func TestSplitRules(t *testing.T) {
+	for tag, want := range map[string][]string{
+		"required":                  {"required"},
+		"required, min=3 ,max=5":    {"required", "min=3", "max=5"},
+		"required,pattern=^[a,b]+$": {"required", "pattern=^[a,b]+$"},
+		"":                          {},
+	} {
+		if got := splitRules(tag); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitRules(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
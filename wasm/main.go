@@ -8,8 +8,11 @@ cd -
 */
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"syscall/js"
 )
 
@@ -22,9 +25,44 @@ var (
 	uri       string   = "ws:"
 	ws        js.Value
 	protocol  string = loc.Get("protocol").String()
+
+	queue          []DataEventIn
+	frameRequested bool
+	applyFrame     js.Func
+
+	// uploads are the files being sent, by ref
+	uploads     = map[string]*upload{}
+	uploadCount int
+
+	// hooks are the JS hooks of the elements with lv-hook, by element id
+	hooks = map[string]*hook{}
+	// filled are the elements filled in the frame, the hooks inside them are updated
+	filled []js.Value
+	// evalEnabled is false when the page has <meta name="liveview-eval" content="off">
+	evalEnabled = true
+
+	// failedHandshakes are the websockets closed before opening since the last one opened
+	failedHandshakes int
+
+	// sentModels are the values of each lv-model sent and not yet rendered by the server
+	sentModels = map[string][]string{}
 )
 
+// domEvents are the events of the attributes lv-<event>
+var domEvents = []string{"click", "dblclick", "change", "input", "keyup", "keydown", "keypress", "submit"}
+
+const (
+	// maxSentModels is the number of values of an input remembered until the server renders them
+	maxSentModels = 64
+	// uploadChunkSize is the size of the pieces of the files, as view.UploadChunkSize
+	uploadChunkSize = 64 << 10
+)
+
+// protocolVersion is the version of the messages sent to the server
+const protocolVersion = 1
+
 type MsgEvent struct {
+	V     int    `json:"v"`
 	Type  string `json:"type"`
 	ID    string `json:"id"`
 	Event string `json:"event"`
@@ -38,9 +76,62 @@ type DataEventIn struct {
 	Value     interface{} `json:"value"`
 	Propertie string      `json:"propertie"`
 	SubType   string      `json:"sub_type"`
+	Kind      string      `json:"kind"`
+	Ref       string      `json:"ref"`
+	Name      string      `json:"name"`
+	Event     string      `json:"event"`
+	Code      string      `json:"code"`
+	// Messages has the messages of a "frame", applied together in one animation frame
+	Messages []DataEventIn `json:"messages"`
+}
+
+type MsgModel struct {
+	V     int    `json:"v"`
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Field string `json:"field"`
+	Data  string `json:"data"`
+}
+
+type MsgUpload struct {
+	V    int    `json:"v"`
+	Type string `json:"type"`
+	Ref  string `json:"ref"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	File string `json:"file,omitempty"`
+	Mime string `json:"mime,omitempty"`
+	Size int    `json:"size,omitempty"`
+	Data string `json:"data,omitempty"`
+}
+
+// upload is a file of an input with lv-upload sent in pieces, each one after the ack
+// of the previous one
+type upload struct {
+	ref   string
+	owner string
+	name  string
+	file  js.Value
+	size  int
+}
+
+// hook is an instance of a hook of window.liveview.hooks attached to an element
+type hook struct {
+	el       js.Value
+	obj      js.Value
+	handlers map[string]js.Value
+	funcs    []js.Func
+}
+
+type MsgNavigate struct {
+	V    int    `json:"v"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	Kind string `json:"kind"`
 }
 
 type DataEventOut struct {
+	V     int         `json:"v"`
 	Type  string      `json:"type"`
 	IdRet string      `json:"id_ret"`
 	Data  interface{} `json:"data"`
@@ -61,10 +152,13 @@ func connect() {
 	}
 	fmt.Println("protocol: " + protocol + " uri: " + uri)
 	uri += "//" + loc.Get("host").String()
-	uri += loc.Get("pathname").String() + "ws_goliveview"
+	uri += loc.Get("pathname").String() + "ws_goliveview" + withCSRF(loc.Get("search").String())
 	ws = webSocket.New(uri)
+	opened := false
 
 	handlerOnOpen := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		opened = true
+		failedHandshakes = 0
 		fmt.Println(ws.Get("readyState").Int())
 		fmt.Println("Connected...ok!!")
 		return nil
@@ -78,6 +172,9 @@ func connect() {
 		}()
 		fmt.Println(ws)
 		fmt.Println("Disconnected...ok")
+		if !opened {
+			handshakeFailed()
+		}
 		document.Call("getElementById", "content").Set("innerHTML", "Disconnected")
 		return nil
 	})
@@ -86,77 +183,10 @@ func connect() {
 		evtData := args[0].Get("data").String()
 		var dataEventIn DataEventIn
 		json.Unmarshal([]byte(evtData), &dataEventIn)
-		currentElement := document.Call("getElementById", dataEventIn.ID)
-
-		if currentElement.IsNull() {
-			return nil
-		}
-
-		if dataEventIn.Type == "fill" {
-			fmt.Println("fill")
-			currentElement.Set("innerHTML", dataEventIn.Value)
-			return nil
-		}
-
-		if dataEventIn.Type == "remove" {
-			currentElement.Call("remove")
-		}
-
-		if dataEventIn.Type == "addNode" {
-			var d = document.Call("createElement", "div")
-			currentElement.Set("innerHTML", fmt.Sprint(dataEventIn.Value))
-			currentElement.Call("appendChild", d)
-		}
-
-		if dataEventIn.Type == "text" {
-			if dataEventIn.Value != "" {
-				currentElement.Set("innerText", dataEventIn.Value)
-			}
-		}
-
-		if dataEventIn.Type == "style" {
-			currentElement.Get("style").Set("cssText", dataEventIn.Value)
-		}
-
-		if dataEventIn.Type == "set" {
-			currentElement.Set("value", dataEventIn.Value)
-		}
-
-		if dataEventIn.Type == "script" {
-			currentElement.Call("eval", dataEventIn.Value)
-		}
-
-		if dataEventIn.Type == "propertie" {
-			currentElement.Set(dataEventIn.Propertie, dataEventIn.Value)
-		}
-
-		if dataEventIn.Type == "get" {
-			dataEventOut := DataEventOut{}
-			dataEventOut.Type = "get"
-			dataEventOut.IdRet = dataEventIn.IdRet
-			if dataEventIn.SubType == "value" {
-				value := currentElement.Get("value")
-				dataEventOut.Data = GetValue(value)
-			}
-			if dataEventIn.SubType == "html" {
-				value := currentElement.Get("innerHTML")
-				dataEventOut.Data = GetValue(value)
-			}
-			if dataEventIn.SubType == "text" {
-				value := currentElement.Get("innerText")
-				dataEventOut.Data = GetValue(value)
-			}
-			if dataEventIn.SubType == "style" {
-				value := currentElement.Get("style").Get(fmt.Sprint(dataEventIn.Value))
-				dataEventOut.Data = GetValue(value)
-			}
-
-			if dataEventIn.SubType == "propertie" {
-				prop := currentElement.Get(fmt.Sprint(dataEventIn.Value))
-				dataEventOut.Data = GetValue(prop)
-			}
-			jsonBytes, _ := json.Marshal(&dataEventOut)
-			ws.Call("send", string(jsonBytes))
+		if dataEventIn.Type == "frame" {
+			enqueue(dataEventIn.Messages...)
+		} else {
+			enqueue(dataEventIn)
 		}
 		return nil
 	})
@@ -169,7 +199,60 @@ func connect() {
 
 }
 
+// withCSRF add the token of <meta name="liveview-csrf"> to the query of the websocket url
+func withCSRF(search string) string {
+	meta := document.Call("querySelector", `meta[name="liveview-csrf"]`)
+	if meta.IsNull() {
+		return search
+	}
+	token := js.Global().Call("encodeURIComponent", meta.Call("getAttribute", "content")).String()
+	if search == "" || search == "?" {
+		return "?lv_csrf=" + token
+	}
+	return search + "&lv_csrf=" + token
+}
+
+// setCSRF replace the token of <meta name="liveview-csrf">, the server sends the token of
+// the page mounted by the live navigation for the next connections
+func setCSRF(token string) {
+	meta := document.Call("querySelector", `meta[name="liveview-csrf"]`)
+	if meta.IsNull() {
+		meta = document.Call("createElement", "meta")
+		meta.Call("setAttribute", "name", "liveview-csrf")
+		document.Get("head").Call("appendChild", meta)
+	}
+	meta.Call("setAttribute", "content", token)
+}
+
+// handshakeFailed reload the page when the server keeps rejecting the websocket, the token
+// of the page expired or the server changed its secret. It reloads once per minute at most
+func handshakeFailed() {
+	failedHandshakes++
+	if failedHandshakes < 3 {
+		return
+	}
+	storage := window.Get("sessionStorage")
+	if storage.IsUndefined() || storage.IsNull() {
+		return
+	}
+	now := js.Global().Get("Date").Call("now").Float()
+	last := storage.Call("getItem", "liveview_reload")
+	if !last.IsNull() {
+		if t, err := strconv.ParseFloat(last.String(), 64); err == nil && now-t < 60000 {
+			return
+		}
+	}
+	storage.Call("setItem", "liveview_reload", strconv.FormatFloat(now, 'f', 0, 64))
+	loc.Call("reload")
+}
+
 func main() {
+	applyFrame = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		flushQueue()
+		return nil
+	})
+	evalEnabled = document.Call("querySelector", `meta[name="liveview-eval"][content="off"]`).IsNull()
+	liveviewObject()
 	document.Call("getElementById", "content").Set("innerHTML", "Disconnected2")
 	connect()
 
@@ -180,6 +263,80 @@ func main() {
 		return nil
 	}), 1000)
 
+	// live navigation, the links with lv-navigate or lv-patch change the url without reloading the page
+	document.Call("addEventListener", "click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := args[0]
+		if event.Get("defaultPrevented").Bool() || event.Get("button").Int() != 0 ||
+			event.Get("ctrlKey").Bool() || event.Get("metaKey").Bool() || event.Get("shiftKey").Bool() {
+			return nil
+		}
+		link := event.Get("target").Call("closest", "a[lv-navigate],a[lv-patch]")
+		if link.IsNull() || link.Get("origin").String() != loc.Get("origin").String() {
+			return nil
+		}
+		event.Call("preventDefault")
+		kind := "navigate"
+		if link.Call("hasAttribute", "lv-patch").Bool() {
+			kind = "patch"
+		}
+		url := link.Get("pathname").String() + link.Get("search").String()
+		window.Get("history").Call("pushState", nil, "", url)
+		sendNavigate(url, kind)
+		return nil
+	}))
+	window.Call("addEventListener", "popstate", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		sendNavigate(loc.Get("pathname").String()+loc.Get("search").String(), "pop")
+		return nil
+	}))
+
+	// the elements with lv-click="Event", lv-change, lv-input, lv-keyup... send the event to
+	// their component, so the pages do not need inline handlers
+	for _, name := range domEvents {
+		name := name
+		document.Call("addEventListener", name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			domEvent(name, args[0])
+			return nil
+		}))
+	}
+
+	// two-way binding, the inputs with lv-model="Field" set the field of their component
+	document.Call("addEventListener", "input", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		target := args[0].Get("target")
+		if target.Get("hasAttribute").Type() != js.TypeFunction || !target.Call("hasAttribute", "lv-model").Bool() {
+			return nil
+		}
+		sendModel(target)
+		return nil
+	}))
+
+	// uploads, the files of the inputs with lv-upload="name" are sent when they are chosen
+	document.Call("addEventListener", "change", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		target := args[0].Get("target")
+		if target.Get("hasAttribute").Type() != js.TypeFunction || !target.Call("hasAttribute", "lv-upload").Bool() {
+			return nil
+		}
+		files := target.Get("files")
+		for i := 0; i < files.Length(); i++ {
+			startUpload(target, files.Index(i))
+		}
+		return nil
+	}))
+	// the buttons with lv-upload-cancel="name" cancel the uploads of the input name
+	document.Call("addEventListener", "click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		button := args[0].Get("target").Call("closest", "[lv-upload-cancel]")
+		if button.IsNull() {
+			return nil
+		}
+		owner := modelOwner(button)
+		name := button.Call("getAttribute", "lv-upload-cancel").String()
+		for _, u := range uploads {
+			if u.owner == owner && u.name == name {
+				cancelUpload(u)
+			}
+		}
+		return nil
+	}))
+
 	js.Global().Set("ws", ws)
 	js.Global().Set("connect", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		connect()
@@ -199,6 +356,215 @@ func main() {
 	<-make(chan struct{})
 }
 
+// enqueue keep the messages in order until the next animation frame, when the page
+// is hidden there are no animation frames so they are applied now
+func enqueue(msgs ...DataEventIn) {
+	queue = append(queue, msgs...)
+	if document.Get("hidden").Truthy() {
+		flushQueue()
+		return
+	}
+	if !frameRequested {
+		frameRequested = true
+		window.Call("requestAnimationFrame", applyFrame)
+	}
+}
+
+func flushQueue() {
+	frameRequested = false
+	msgs := queue
+	queue = nil
+	for _, msg := range msgs {
+		applyMessage(msg)
+	}
+	syncHooks()
+}
+
+func applyMessage(dataEventIn DataEventIn) {
+	switch dataEventIn.Type {
+	case "url":
+		url := fmt.Sprint(dataEventIn.Value)
+		if dataEventIn.Kind == "replace" {
+			window.Get("history").Call("replaceState", nil, "", url)
+		} else {
+			window.Get("history").Call("pushState", nil, "", url)
+		}
+		return
+	case "title":
+		document.Set("title", dataEventIn.Value)
+		return
+	case "redirect":
+		loc.Call("assign", dataEventIn.Value)
+		return
+	case "csrf":
+		setCSRF(fmt.Sprint(dataEventIn.Value))
+		return
+	case "upload":
+		uploadReply(dataEventIn)
+		return
+	case "hook_event":
+		pushHookEvent(dataEventIn)
+		return
+	case "error":
+		// the server ignored a message, the page can listen to lv-error
+		console.Call("warn", "liveview: "+dataEventIn.Code+": "+fmt.Sprint(dataEventIn.Value))
+		detail := map[string]interface{}{"code": dataEventIn.Code, "message": fmt.Sprint(dataEventIn.Value)}
+		document.Call("dispatchEvent", js.Global().Get("CustomEvent").New("lv-error", map[string]interface{}{"detail": detail}))
+		return
+	case "script":
+		if !evalEnabled {
+			console.Call("warn", "liveview: eval is disabled")
+			return
+		}
+		evalScript(fmt.Sprint(dataEventIn.Value))
+		return
+	case "download":
+		link := document.Call("createElement", "a")
+		link.Set("href", dataEventIn.Value)
+		link.Set("download", dataEventIn.Name)
+		link.Get("style").Set("display", "none")
+		document.Get("body").Call("appendChild", link)
+		link.Call("click")
+		link.Call("remove")
+		return
+	}
+
+	currentElement := document.Call("getElementById", dataEventIn.ID)
+
+	if currentElement.IsNull() {
+		// the server waits the reply of a get, even without the element
+		if dataEventIn.Type == "get" {
+			jsonBytes, _ := json.Marshal(&DataEventOut{V: protocolVersion, Type: "get", IdRet: dataEventIn.IdRet})
+			ws.Call("send", string(jsonBytes))
+		}
+		return
+	}
+
+	if dataEventIn.Type == "fill" {
+		fill(currentElement, dataEventIn.Value)
+		filled = append(filled, currentElement)
+		return
+	}
+
+	if dataEventIn.Type == "each" {
+		if ids, ok := dataEventIn.Value.([]interface{}); ok {
+			reorder(currentElement, ids)
+		}
+		return
+	}
+
+	if dataEventIn.Type == "remove" {
+		currentElement.Call("remove")
+	}
+
+	if dataEventIn.Type == "addNode" {
+		var d = document.Call("createElement", "div")
+		currentElement.Set("innerHTML", fmt.Sprint(dataEventIn.Value))
+		currentElement.Call("appendChild", d)
+	}
+
+	if dataEventIn.Type == "text" {
+		if dataEventIn.Value != "" {
+			currentElement.Set("innerText", dataEventIn.Value)
+		}
+	}
+
+	if dataEventIn.Type == "style" {
+		currentElement.Get("style").Set("cssText", dataEventIn.Value)
+	}
+
+	if dataEventIn.Type == "set" {
+		currentElement.Set("value", dataEventIn.Value)
+	}
+
+	if dataEventIn.Type == "propertie" {
+		currentElement.Set(dataEventIn.Propertie, dataEventIn.Value)
+	}
+
+	if dataEventIn.Type == "get" {
+		dataEventOut := DataEventOut{V: protocolVersion}
+		dataEventOut.Type = "get"
+		dataEventOut.IdRet = dataEventIn.IdRet
+		if dataEventIn.SubType == "value" {
+			value := currentElement.Get("value")
+			dataEventOut.Data = GetValue(value)
+		}
+		if dataEventIn.SubType == "html" {
+			value := currentElement.Get("innerHTML")
+			dataEventOut.Data = GetValue(value)
+		}
+		if dataEventIn.SubType == "text" {
+			value := currentElement.Get("innerText")
+			dataEventOut.Data = GetValue(value)
+		}
+		if dataEventIn.SubType == "style" {
+			value := currentElement.Get("style").Get(fmt.Sprint(dataEventIn.Value))
+			dataEventOut.Data = GetValue(value)
+		}
+
+		if dataEventIn.SubType == "propertie" {
+			prop := currentElement.Get(fmt.Sprint(dataEventIn.Value))
+			dataEventOut.Data = GetValue(prop)
+		}
+		jsonBytes, _ := json.Marshal(&dataEventOut)
+		ws.Call("send", string(jsonBytes))
+	}
+}
+
+// fill replace the content of element keeping the nodes of the keyed lists (lv-each),
+// their children are managed by the "each" messages, and the input with the focus
+func fill(element js.Value, value interface{}) {
+	lists := map[string]js.Value{}
+	nodes := element.Call("querySelectorAll", "[lv-each]")
+	for i := 0; i < nodes.Length(); i++ {
+		node := nodes.Index(i)
+		lists[node.Get("id").String()] = node
+	}
+	focused, focusedKey := focusedModel(element)
+	element.Set("innerHTML", value)
+	if focusedKey != "" {
+		keepFocused(element, focused, focusedKey)
+	}
+	if len(lists) == 0 {
+		return
+	}
+	nodes = element.Call("querySelectorAll", "[lv-each]")
+	for i := 0; i < nodes.Length(); i++ {
+		node := nodes.Index(i)
+		if old, ok := lists[node.Get("id").String()]; ok {
+			node.Call("replaceWith", old)
+		}
+	}
+}
+
+// reorder make the children of container match ids, moving only the nodes out of place
+func reorder(container js.Value, ids []interface{}) {
+	existing := map[string]js.Value{}
+	children := container.Get("children")
+	for i := 0; i < children.Length(); i++ {
+		child := children.Index(i)
+		existing[child.Get("id").String()] = child
+	}
+	for i, id := range ids {
+		key := fmt.Sprint(id)
+		node, ok := existing[key]
+		if !ok {
+			node = document.Call("createElement", "div")
+			node.Set("id", key)
+		}
+		delete(existing, key)
+		current := children.Index(i)
+		if current.IsUndefined() || current.IsNull() {
+			container.Call("appendChild", node)
+		} else if !current.Equal(node) {
+			container.Call("insertBefore", node, current)
+		}
+	}
+	for _, node := range existing {
+		node.Call("remove")
+	}
+}
+
 func GetValue(prop js.Value) interface{} {
 	switch prop.Type() {
 	case js.TypeBoolean:
@@ -217,6 +583,7 @@ func GetValue(prop js.Value) interface{} {
 
 func sendEvent(id string, event string, data string) {
 	msgEvent := MsgEvent{
+		V:     protocolVersion,
 		Type:  "data",
 		ID:    id,
 		Event: event,
@@ -225,3 +592,431 @@ func sendEvent(id string, event string, data string) {
 	jsonMsg, _ := json.Marshal(&msgEvent)
 	ws.Call("send", string(jsonMsg))
 }
+
+// domEvent send the event of the element with the attribute lv-<name> to its component,
+// the one of lv-target or the one rendering it. The data of the event is lv-value, the
+// value of the input, or both as "lv-value:value". With lv-key="Enter" the key events
+// are sent only for that key
+func domEvent(name string, event js.Value) {
+	target := event.Get("target")
+	if target.Get("closest").Type() != js.TypeFunction {
+		return
+	}
+	el := target.Call("closest", "[lv-"+name+"]")
+	if el.IsNull() {
+		return
+	}
+	if name == "submit" {
+		event.Call("preventDefault")
+	}
+	if key := el.Call("getAttribute", "lv-key"); !key.IsNull() && strings.HasPrefix(name, "key") &&
+		key.String() != event.Get("key").String() {
+		return
+	}
+	owner := el.Call("getAttribute", "lv-target")
+	id := ""
+	if owner.IsNull() {
+		id = modelOwner(el)
+	} else {
+		id = owner.String()
+	}
+	if id == "" {
+		return
+	}
+	sendEvent(id, el.Call("getAttribute", "lv-"+name).String(), eventValue(el))
+}
+
+// eventValue return the data of an event of el
+func eventValue(el js.Value) string {
+	value := el.Call("getAttribute", "lv-value")
+	switch el.Get("tagName").String() {
+	case "INPUT", "SELECT", "TEXTAREA":
+		current := el.Get("value").String()
+		if el.Get("type").String() == "checkbox" {
+			current = fmt.Sprint(el.Get("checked").Bool())
+		}
+		if value.IsNull() {
+			return current
+		}
+		return value.String() + ":" + current
+	}
+	if value.IsNull() {
+		return ""
+	}
+	return value.String()
+}
+
+// modelOwner return the id of the component of an input with lv-model, the page is "content"
+func modelOwner(input js.Value) string {
+	owner := input.Call("closest", "[id^='mount_span_'],#content")
+	if owner.IsNull() {
+		return ""
+	}
+	return strings.TrimPrefix(owner.Get("id").String(), "mount_span_")
+}
+
+func modelKey(input js.Value) string {
+	owner := modelOwner(input)
+	if owner == "" {
+		return ""
+	}
+	return owner + "." + input.Call("getAttribute", "lv-model").String()
+}
+
+func sendModel(input js.Value) {
+	owner := modelOwner(input)
+	if owner == "" {
+		return
+	}
+	value := input.Get("value").String()
+	switch input.Get("type").String() {
+	case "checkbox":
+		value = fmt.Sprint(input.Get("checked").Bool())
+	case "radio":
+		if !input.Get("checked").Bool() {
+			return
+		}
+	}
+	field := input.Call("getAttribute", "lv-model").String()
+	key := owner + "." + field
+	sent := append(sentModels[key], value)
+	if len(sent) > maxSentModels {
+		sent = sent[len(sent)-maxSentModels:]
+	}
+	sentModels[key] = sent
+	jsonMsg, _ := json.Marshal(&MsgModel{V: protocolVersion, Type: "model", ID: owner, Field: field, Data: value})
+	ws.Call("send", string(jsonMsg))
+}
+
+// focusedModel return the text input with lv-model and the focus inside element
+func focusedModel(element js.Value) (js.Value, string) {
+	active := document.Get("activeElement")
+	if active.IsNull() || active.Get("selectionStart").IsUndefined() ||
+		!active.Call("hasAttribute", "lv-model").Bool() || !element.Call("contains", active).Bool() {
+		return js.Null(), ""
+	}
+	return active, modelKey(active)
+}
+
+// keepFocused put back the input with the focus in place of its new render, so the user
+// keeps typing in it. The value rendered is applied only if it is not a value sent by
+// the input, and then the cursor is kept where it was
+func keepFocused(element js.Value, focused js.Value, key string) {
+	nodes := element.Call("querySelectorAll", "[lv-model]")
+	for i := 0; i < nodes.Length(); i++ {
+		node := nodes.Index(i)
+		if modelKey(node) != key {
+			continue
+		}
+		syncAttributes(focused, node)
+		rendered := node.Get("value").String()
+		if !consumeSentModel(key, rendered) && rendered != focused.Get("value").String() {
+			start := focused.Get("selectionStart")
+			end := focused.Get("selectionEnd")
+			focused.Set("value", rendered)
+			if !start.IsNull() {
+				max := len([]rune(rendered))
+				focused.Call("setSelectionRange", minInt(start.Int(), max), minInt(end.Int(), max))
+			}
+		}
+		node.Call("replaceWith", focused)
+		focused.Call("focus")
+		return
+	}
+}
+
+// consumeSentModel forget the values sent until value, it return false when value was not sent
+func consumeSentModel(key string, value string) bool {
+	sent := sentModels[key]
+	for i, v := range sent {
+		if v == value {
+			sentModels[key] = sent[i+1:]
+			return true
+		}
+	}
+	return false
+}
+
+// syncAttributes copy the attributes of from to to, but the value
+func syncAttributes(to js.Value, from js.Value) {
+	names := map[string]bool{}
+	attrs := from.Get("attributes")
+	for i := 0; i < attrs.Length(); i++ {
+		attr := attrs.Index(i)
+		name := attr.Get("name").String()
+		names[name] = true
+		if name != "value" {
+			to.Call("setAttribute", name, attr.Get("value"))
+		}
+	}
+	attrs = to.Get("attributes")
+	for i := attrs.Length() - 1; i >= 0; i-- {
+		name := attrs.Index(i).Get("name").String()
+		if !names[name] {
+			to.Call("removeAttribute", name)
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func startUpload(input js.Value, file js.Value) {
+	owner := modelOwner(input)
+	if owner == "" {
+		return
+	}
+	uploadCount++
+	u := &upload{
+		ref:   fmt.Sprintf("%s-%d-%d", owner, js.Global().Get("Date").Call("now").Int(), uploadCount),
+		owner: owner,
+		name:  input.Call("getAttribute", "lv-upload").String(),
+		file:  file,
+		size:  file.Get("size").Int(),
+	}
+	uploads[u.ref] = u
+	sendUpload(MsgUpload{Type: "upload_start", Ref: u.ref, ID: owner, Name: u.name,
+		File: file.Get("name").String(), Mime: file.Get("type").String(), Size: u.size})
+	uploadProgress(u, 0, "start", "")
+}
+
+// uploadReply apply the answer of the server: "ack" with the bytes received asks for
+// the next piece, "done", "error" or "cancel" finish the upload
+func uploadReply(msg DataEventIn) {
+	u, ok := uploads[msg.Ref]
+	if !ok {
+		return
+	}
+	switch msg.Kind {
+	case "ack":
+		offset := 0
+		if n, ok := msg.Value.(float64); ok {
+			offset = int(n)
+		}
+		uploadProgress(u, offset, "progress", "")
+		sendChunk(u, offset)
+	case "done":
+		delete(uploads, u.ref)
+		uploadProgress(u, u.size, "done", "")
+	default:
+		delete(uploads, u.ref)
+		uploadProgress(u, 0, msg.Kind, fmt.Sprint(msg.Value))
+	}
+}
+
+// sendChunk read the piece of the file at offset and send it
+func sendChunk(u *upload, offset int) {
+	end := offset + uploadChunkSize
+	if end > u.size {
+		end = u.size
+	}
+	var then js.Func
+	then = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		then.Release()
+		if _, ok := uploads[u.ref]; !ok {
+			return nil
+		}
+		array := js.Global().Get("Uint8Array").New(args[0])
+		chunk := make([]byte, array.Length())
+		js.CopyBytesToGo(chunk, array)
+		sendUpload(MsgUpload{Type: "upload_chunk", Ref: u.ref, Data: base64.StdEncoding.EncodeToString(chunk)})
+		return nil
+	})
+	u.file.Call("slice", offset, end).Call("arrayBuffer").Call("then", then)
+}
+
+func cancelUpload(u *upload) {
+	delete(uploads, u.ref)
+	sendUpload(MsgUpload{Type: "upload_cancel", Ref: u.ref})
+	uploadProgress(u, 0, "cancel", "")
+}
+
+// uploadProgress update the elements lv-upload-progress="name" of the component, a
+// <progress> for example, and dispatch the event lv-upload on the document with the state
+func uploadProgress(u *upload, loaded int, state string, message string) {
+	percent := 100
+	if u.size > 0 {
+		percent = loaded * 100 / u.size
+	}
+	root := document
+	if owner := document.Call("getElementById", "mount_span_"+u.owner); !owner.IsNull() {
+		root = owner
+	}
+	nodes := root.Call("querySelectorAll", "[lv-upload-progress='"+u.name+"']")
+	for i := 0; i < nodes.Length(); i++ {
+		node := nodes.Index(i)
+		node.Set("max", 100)
+		node.Set("value", percent)
+		node.Call("setAttribute", "data-state", state)
+	}
+	detail := map[string]interface{}{
+		"ref": u.ref, "name": u.name, "file": u.file.Get("name").String(),
+		"loaded": loaded, "total": u.size, "percent": percent, "state": state, "message": message,
+	}
+	event := js.Global().Get("CustomEvent").New("lv-upload", map[string]interface{}{"detail": detail})
+	document.Call("dispatchEvent", event)
+}
+
+func sendUpload(msg MsgUpload) {
+	msg.V = protocolVersion
+	jsonMsg, _ := json.Marshal(&msg)
+	ws.Call("send", string(jsonMsg))
+}
+
+// evalScript run code in the global scope, the errors are logged
+func evalScript(code string) {
+	defer func() {
+		if r := recover(); r != nil {
+			console.Call("error", "liveview: eval:", fmt.Sprint(r))
+		}
+	}()
+	js.Global().Call("eval", code)
+}
+
+// liveviewObject return window.liveview, the page registers its hooks in liveview.hooks
+// before or after the client starts
+func liveviewObject() js.Value {
+	lv := js.Global().Get("liveview")
+	if lv.IsUndefined() || lv.IsNull() {
+		lv = js.Global().Get("Object").New()
+		js.Global().Set("liveview", lv)
+	}
+	if lv.Get("hooks").IsUndefined() {
+		lv.Set("hooks", js.Global().Get("Object").New())
+	}
+	return lv
+}
+
+// syncHooks mount the hooks of the new elements with lv-hook, update the ones rendered
+// again and destroy the ones removed
+func syncHooks() {
+	seen := map[string]bool{}
+	nodes := document.Call("querySelectorAll", "[lv-hook]")
+	for i := 0; i < nodes.Length(); i++ {
+		node := nodes.Index(i)
+		id := node.Get("id").String()
+		if id == "" {
+			console.Call("warn", "liveview: an element with lv-hook needs an id", node)
+			continue
+		}
+		seen[id] = true
+		h, ok := hooks[id]
+		if !ok {
+			mountHook(id, node)
+			continue
+		}
+		if !h.el.Equal(node) {
+			h.el = node
+			h.obj.Set("el", node)
+			callHook(h, "updated")
+			continue
+		}
+		if wasFilled(node) {
+			callHook(h, "updated")
+		}
+	}
+	for id, h := range hooks {
+		if !seen[id] {
+			callHook(h, "destroyed")
+			for _, f := range h.funcs {
+				f.Release()
+			}
+			delete(hooks, id)
+		}
+	}
+	filled = nil
+}
+
+func wasFilled(node js.Value) bool {
+	for _, f := range filled {
+		if node.Call("contains", f).Bool() {
+			return true
+		}
+	}
+	return false
+}
+
+func mountHook(id string, el js.Value) {
+	name := el.Call("getAttribute", "lv-hook").String()
+	def := liveviewObject().Get("hooks").Get(name)
+	if def.IsUndefined() || def.IsNull() {
+		console.Call("warn", "liveview: unknown hook "+name)
+		return
+	}
+	h := &hook{el: el, handlers: map[string]js.Value{}}
+	h.obj = js.Global().Get("Object").Call("create", def)
+	h.obj.Set("el", el)
+	pushEvent := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			return nil
+		}
+		var payload interface{}
+		if len(args) > 1 {
+			payload = args[1]
+		}
+		msg := js.ValueOf(map[string]interface{}{"v": protocolVersion, "type": "data", "id": modelOwner(h.el), "event": args[0].String(), "data": payload})
+		ws.Call("send", js.Global().Get("JSON").Call("stringify", msg))
+		return nil
+	})
+	handleEvent := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 2 {
+			h.handlers[args[0].String()] = args[1]
+		}
+		return nil
+	})
+	h.funcs = append(h.funcs, pushEvent, handleEvent)
+	h.obj.Set("pushEvent", pushEvent)
+	h.obj.Set("handleEvent", handleEvent)
+	hooks[id] = h
+	callHook(h, "mounted")
+}
+
+// callHook invoke the callback name of the hook, the errors are logged
+func callHook(h *hook, name string) {
+	defer func() {
+		if r := recover(); r != nil {
+			console.Call("error", "liveview: hook "+name+":", fmt.Sprint(r))
+		}
+	}()
+	if fn := h.obj.Get(name); fn.Type() == js.TypeFunction {
+		fn.Call("call", h.obj)
+	}
+}
+
+// pushHookEvent deliver the PushEvent of the component msg.ID to its hooks
+func pushHookEvent(msg DataEventIn) {
+	syncHooks()
+	owner := document.Call("getElementById", msg.ID)
+	if owner.IsNull() {
+		return
+	}
+	for _, h := range hooks {
+		fn, ok := h.handlers[msg.Event]
+		if !ok || !owner.Call("contains", h.el).Bool() {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					console.Call("error", "liveview: hook event "+msg.Event+":", fmt.Sprint(r))
+				}
+			}()
+			fn.Call("call", h.obj, js.ValueOf(msg.Value))
+		}()
+	}
+}
+
+func sendNavigate(url string, kind string) {
+	msgNavigate := MsgNavigate{
+		V:    protocolVersion,
+		Type: "navigate",
+		URL:  url,
+		Kind: kind,
+	}
+	jsonMsg, _ := json.Marshal(&msgNavigate)
+	ws.Call("send", string(jsonMsg))
+}